@@ -1,74 +1,81 @@
 package main
 
 import (
+	"bytes"
 	"fmt"
 	s "github.com/rookiecj/go-stream/stream"
+	"strings"
 )
 
 // From https://jsonplaceholder.typicode.com/posts
 
 type Todo struct {
-	userId int    `json:"userId"`
-	id     int    `json:"id"`
-	title  string `json:"title"`
-	body   string `json:"body"`
-	done   bool
+	UserId int    `json:"userId" csv:"userId"`
+	Id     int    `json:"id" csv:"id"`
+	Title  string `json:"title" csv:"title"`
+	Body   string `json:"body" csv:"body"`
+	Done   bool   `json:"done,omitempty" csv:"done"`
+}
+
+type User struct {
+	Id   int    `json:"id"`
+	Name string `json:"name"`
 }
 
 var Todos = []Todo{
 	{
-		userId: 1,
-		id:     1,
-		title:  "sunt aut facere repellat provident occaecati excepturi optio reprehenderit",
-		body:   "quia et suscipit\nsuscipit recusandae consequuntur expedita et cum\nreprehenderit molestiae ut ut quas totam\nnostrum rerum est autem sunt rem eveniet architecto",
+		UserId: 1,
+		Id:     1,
+		Title:  "sunt aut facere repellat provident occaecati excepturi optio reprehenderit",
+		Body:   "quia et suscipit\nsuscipit recusandae consequuntur expedita et cum\nreprehenderit molestiae ut ut quas totam\nnostrum rerum est autem sunt rem eveniet architecto",
 	},
 	{
-		userId: 1,
-		id:     2,
-		title:  "qui est esse",
-		body:   "est rerum tempore vitae\nsequi sint nihil reprehenderit dolor beatae ea dolores neque\nfugiat blanditiis voluptate porro vel nihil molestiae ut reiciendis\nqui aperiam non debitis possimus qui neque nisi nulla",
+		UserId: 1,
+		Id:     2,
+		Title:  "qui est esse",
+		Body:   "est rerum tempore vitae\nsequi sint nihil reprehenderit dolor beatae ea dolores neque\nfugiat blanditiis voluptate porro vel nihil molestiae ut reiciendis\nqui aperiam non debitis possimus qui neque nisi nulla",
 	},
 	{
-		userId: 1,
-		id:     3,
-		title:  "ea molestias quasi exercitationem repellat qui ipsa sit aut",
-		body:   "et iusto sed quo iure\nvoluptatem occaecati omnis eligendi aut ad\nvoluptatem doloribus vel accusantium quis pariatur\nmolestiae porro eius odio et labore et velit aut",
+		UserId: 1,
+		Id:     3,
+		Title:  "ea molestias quasi exercitationem repellat qui ipsa sit aut",
+		Body:   "et iusto sed quo iure\nvoluptatem occaecati omnis eligendi aut ad\nvoluptatem doloribus vel accusantium quis pariatur\nmolestiae porro eius odio et labore et velit aut",
 	},
 	{
-		userId: 1,
-		id:     4,
-		title:  "eum et est occaecati",
-		body:   "ullam et saepe reiciendis voluptatem adipisci\nsit amet autem assumenda provident rerum culpa\nquis hic commodi nesciunt rem tenetur doloremque ipsam iure\nquis sunt voluptatem rerum illo velit",
+		UserId: 1,
+		Id:     4,
+		Title:  "eum et est occaecati",
+		Body:   "ullam et saepe reiciendis voluptatem adipisci\nsit amet autem assumenda provident rerum culpa\nquis hic commodi nesciunt rem tenetur doloremque ipsam iure\nquis sunt voluptatem rerum illo velit",
 	},
 	{
-		userId: 1,
-		id:     5,
-		title:  "nesciunt quas odio",
-		body:   "repudiandae veniam quaerat sunt sed\nalias aut fugiat sit autem sed est\nvoluptatem omnis possimus esse voluptatibus quis\nest aut tenetur dolor neque",
+		UserId: 1,
+		Id:     5,
+		Title:  "nesciunt quas odio",
+		Body:   "repudiandae veniam quaerat sunt sed\nalias aut fugiat sit autem sed est\nvoluptatem omnis possimus esse voluptatibus quis\nest aut tenetur dolor neque",
 	},
 	{
-		userId: 2,
-		id:     11,
-		title:  "et ea vero quia laudantium autem",
-		body:   "delectus reiciendis molestiae occaecati non minima eveniet qui voluptatibus\naccusamus in eum beatae sit\nvel qui neque voluptates ut commodi qui incidunt\nut animi commodi",
+		UserId: 2,
+		Id:     11,
+		Title:  "et ea vero quia laudantium autem",
+		Body:   "delectus reiciendis molestiae occaecati non minima eveniet qui voluptatibus\naccusamus in eum beatae sit\nvel qui neque voluptates ut commodi qui incidunt\nut animi commodi",
 	},
 	{
-		userId: 2,
-		id:     12,
-		title:  "in quibusdam tempore odit est dolorem",
-		body:   "itaque id aut magnam\npraesentium quia et ea odit et ea voluptas et\nsapiente quia nihil amet occaecati quia id voluptatem\nincidunt ea est distinctio odio",
+		UserId: 2,
+		Id:     12,
+		Title:  "in quibusdam tempore odit est dolorem",
+		Body:   "itaque id aut magnam\npraesentium quia et ea odit et ea voluptas et\nsapiente quia nihil amet occaecati quia id voluptatem\nincidunt ea est distinctio odio",
 	},
 	{
-		userId: 2,
-		id:     13,
-		title:  "dolorum ut in voluptas mollitia et saepe quo animi",
-		body:   "aut dicta possimus sint mollitia voluptas commodi quo doloremque\niste corrupti reiciendis voluptatem eius rerum\nsit cumque quod eligendi laborum minima\nperferendis recusandae assumenda consectetur porro architecto ipsum ipsam",
+		UserId: 2,
+		Id:     13,
+		Title:  "dolorum ut in voluptas mollitia et saepe quo animi",
+		Body:   "aut dicta possimus sint mollitia voluptas commodi quo doloremque\niste corrupti reiciendis voluptatem eius rerum\nsit cumque quod eligendi laborum minima\nperferendis recusandae assumenda consectetur porro architecto ipsum ipsam",
 	},
 	{
-		userId: 2,
-		id:     14,
-		title:  "voluptatem eligendi optio",
-		body:   "fuga et accusamus dolorum perferendis illo voluptas\nnon doloremque neque facere\nad qui dolorum molestiae beatae\nsed aut voluptas totam sit illum",
+		UserId: 2,
+		Id:     14,
+		Title:  "voluptatem eligendi optio",
+		Body:   "fuga et accusamus dolorum perferendis illo voluptas\nnon doloremque neque facere\nad qui dolorum molestiae beatae\nsed aut voluptas totam sit illum",
 	},
 }
 
@@ -128,7 +135,7 @@ func main() {
 	todoS := newTodoSource(Todos)
 	length := s.FromSource[Todo](todoS).
 		Filter(func(todo Todo) bool {
-			return todo.userId == 1
+			return todo.UserId == 1
 		}).
 		Count()
 	fmt.Println("todos for user1 count=", length)
@@ -137,7 +144,7 @@ func main() {
 	todosource := newTodoSource(Todos)
 	todos1 := s.FromSource[Todo](todosource).
 		Filter(func(ele Todo) bool {
-			return ele.userId == 1
+			return ele.UserId == 1
 		}).Collect()
 	fmt.Println("len(todos for user1)=", len(todos1))
 	//len(todos for user1)= 5
@@ -145,10 +152,10 @@ func main() {
 	todosource = newTodoSource(Todos)
 	titlestream := s.FromSource[Todo](todosource).
 		Filter(func(ele Todo) bool {
-			return ele.userId == 2
+			return ele.UserId == 2
 		}).
 		MapAny(func(ele Todo) any {
-			return ele.title
+			return ele.Title
 		})
 	titles := s.CollectAs[string](titlestream)
 	for _, title := range titles {
@@ -162,22 +169,111 @@ func main() {
 	todosourceIndexed := newTodoSourceIndexed(Todos)
 	s.FromSource[s.Indexed[Todo]](todosourceIndexed).
 		Filter(func(ele s.Indexed[Todo]) bool {
-			return ele.Value.userId == 2
+			return ele.Value.UserId == 2
 		}).
 		Map(func(ele s.Indexed[Todo]) s.Indexed[Todo] {
 			dup := ele.Value
-			dup.done = true
+			dup.Done = true
 			return s.Indexed[Todo]{
 				Index: ele.Index,
 				Value: dup,
 			}
 		}).
 		ForEach(func(ele s.Indexed[Todo]) {
-			fmt.Printf("%d: %s\n", ele.Index, ele.Value.title)
+			fmt.Printf("%d: %s\n", ele.Index, ele.Value.Title)
 		})
 	//5: et ea vero quia laudantium autem
 	//6: in quibusdam tempore odit est dolorem
 	//7: dolorum ut in voluptas mollitia et saepe quo animi
 	//8: voluptatem eligendi optio
 
+	todosource = newTodoSource(Todos)
+	titlesByUser := s.GroupByCollect(s.FromSource[Todo](todosource),
+		func(ele Todo) int {
+			return ele.UserId
+		},
+		func(todos s.Stream[Todo]) []string {
+			return s.Map(todos, func(ele Todo) string {
+				return ele.Title
+			}).Collect()
+		})
+	for userId := 1; userId <= len(titlesByUser); userId++ {
+		fmt.Printf("user%d: %d todos, first=%s\n", userId, len(titlesByUser[userId]), titlesByUser[userId][0])
+	}
+	//user1: 5 todos, first=sunt aut facere repellat provident occaecati excepturi optio reprehenderit
+	//user2: 4 todos, first=et ea vero quia laudantium autem
+
+	todosource = newTodoSource(Todos)
+	_, err := s.FromSource[Todo](todosource).
+		Find(func(ele Todo) bool {
+			return ele.Id == 99
+		})
+	fmt.Println("todo 99:", err)
+	//todo 99: stream: not found
+
+	todosource = newTodoSource(Todos)
+	title := s.MapOptional(s.FromSource[Todo](todosource).
+		FindFirst(func(ele Todo) bool {
+			return ele.UserId == 2
+		}), func(ele Todo) string {
+		return ele.Title
+	}).OrElse("none")
+	fmt.Println("first todo for user2:", title)
+	//first todo for user2: et ea vero quia laudantium autem
+
+	var export bytes.Buffer
+	todosource = newTodoSource(Todos)
+	err = s.ToCSV(s.FromSource[Todo](todosource).
+		Filter(func(ele Todo) bool {
+			return ele.UserId == 2
+		}), &export, s.CSVOptions{})
+	if err != nil {
+		fmt.Println("export failed:", err)
+		return
+	}
+	s.FromCSV[Todo](&export, s.CSVOptions{}).
+		ForEach(func(ele Todo) {
+			fmt.Printf("csv %d: %s\n", ele.Id, ele.Title)
+		})
+	//csv 11: et ea vero quia laudantium autem
+	//csv 12: in quibusdam tempore odit est dolorem
+	//csv 13: dolorum ut in voluptas mollitia et saepe quo animi
+	//csv 14: voluptatem eligendi optio
+
+	// as downloaded from https://jsonplaceholder.typicode.com/posts
+	posts := `[
+  {"userId": 3, "id": 21, "title": "asperiores ea ipsam voluptatibus modi minima quia sint", "body": "repellat aliquid praesentium dolorem quo"},
+  {"userId": 3, "id": 22, "title": "dolor sint quo a velit explicabo quia nam", "body": "eos qui et ipsum ipsam suscipit aut"}
+]`
+	user3 := s.FromJSONArray[Todo](strings.NewReader(posts)).
+		Filter(func(ele Todo) bool {
+			return ele.UserId == 3
+		})
+	count, err := s.FoldErr(user3, 0, func(acc int, ele Todo) int {
+		return acc + 1
+	})
+	fmt.Println("todos for user3 from json count=", count, err)
+	//todos for user3 from json count= 2 <nil>
+
+	// From https://jsonplaceholder.typicode.com/users
+	users := []User{
+		{Id: 1, Name: "Leanne Graham"},
+		{Id: 2, Name: "Ervin Howell"},
+	}
+	todosource = newTodoSource(Todos)
+	s.HashJoin(s.FromSource[Todo](todosource).
+		Filter(func(ele Todo) bool {
+			return ele.Id%5 == 1
+		}), s.FromSlice(users),
+		func(ele Todo) int {
+			return ele.UserId
+		},
+		func(ele User) int {
+			return ele.Id
+		}).
+		ForEach(func(ele s.Pair[Todo, User]) {
+			fmt.Printf("%s: %s\n", ele.Second.Name, ele.First.Title)
+		})
+	//Leanne Graham: sunt aut facere repellat provident occaecati excepturi optio reprehenderit
+	//Ervin Howell: et ea vero quia laudantium autem
 }
@@ -2,4 +2,4 @@ module github.com/rookiecj/go-stream
 
 go 1.18
 
-require github.com/mattn/go-sqlite3 v1.14.19 // indirect
+require github.com/mattn/go-sqlite3 v1.14.19
@@ -1,5 +1,9 @@
 package stream
 
+import (
+	"context"
+)
+
 //
 // stream builders
 //
@@ -24,6 +28,12 @@ func FromSlice[T any](arr []T) Stream[T] {
 	stream.getonrecover = func() RecoverFunc {
 		return nil
 	}
+	stream.geterr = func() error {
+		return nil
+	}
+	stream.close = func() error {
+		return nil
+	}
 	return stream
 }
 
@@ -45,6 +55,12 @@ func FromVar[T any](arr ...T) Stream[T] {
 	stream.getonrecover = func() RecoverFunc {
 		return nil
 	}
+	stream.geterr = func() error {
+		return nil
+	}
+	stream.close = func() error {
+		return nil
+	}
 	return stream
 }
 
@@ -78,14 +94,71 @@ func FromChan[T any](ch <-chan T) Stream[T] {
 	stream.getonrecover = func() RecoverFunc {
 		return nil
 	}
+	stream.geterr = func() error {
+		return nil
+	}
+	stream.close = func() error {
+		return nil
+	}
+	return stream
+}
+
+// FromChanCtx build a Stream from given channel which ends when the given context is done.
+// waiting for an element is unblocked by the context, and Err of the stream returns ctx.Err().
+func FromChanCtx[T any](ctx context.Context, ch <-chan T) Stream[T] {
+	stream := new(baseStream[T])
+	stream.idx = -1
+	var v T
+	var err error
+	stream.next = func() bool {
+		if err != nil {
+			return false
+		}
+		if err = ctx.Err(); err != nil || ch == nil {
+			return false
+		}
+		var ok bool
+		select {
+		case v, ok = <-ch:
+			if ok {
+				stream.idx++
+			}
+			return ok
+		case <-ctx.Done():
+			err = ctx.Err()
+			return false
+		}
+	}
+
+	stream.get = func() any {
+		return v
+	}
+	stream.getonrecover = func() RecoverFunc {
+		return nil
+	}
+	stream.geterr = func() error {
+		return err
+	}
+	stream.close = func() error {
+		return nil
+	}
 	return stream
 }
 
+// FromSource build a Stream from given Source.
+// if the source is an ErrSource, its error is reported by Err of the stream.
+// if the source is an io.Closer, it is closed once when the stream is closed.
+// if Next of the source panics, the stream ends after the panic.
 func FromSource[T any](source Source[T]) Stream[T] {
 	stream := new(baseStream[T])
 	stream.idx = -1
+	closed := false
+	failed := false
 	stream.next = func() bool {
-		if source.Next() {
+		if failed {
+			return false
+		}
+		if sourceNext(source, &failed) {
 			stream.idx++
 			return true
 		}
@@ -98,5 +171,15 @@ func FromSource[T any](source Source[T]) Stream[T] {
 	stream.getonrecover = func() RecoverFunc {
 		return nil
 	}
+	stream.geterr = func() error {
+		return sourceErr(source)
+	}
+	stream.close = func() error {
+		if closed {
+			return nil
+		}
+		closed = true
+		return closeSource(source)
+	}
 	return stream
 }
@@ -0,0 +1,135 @@
+package stream
+
+import (
+	"context"
+	"sync"
+)
+
+//
+// channel interop
+//
+
+// FromProducer build a Stream from a push-style producer, which runs in a goroutine when the stream is pulled first.
+// emit sends an element to the stream, it blocks until the element is pulled
+// and returns false when the stream is closed, then the producer should return.
+// the stream ends when the producer returns, and a panic of the producer is raised again on the consuming goroutine.
+func FromProducer[T any](producer func(emit func(T) bool)) Stream[T] {
+	stream := new(baseStream[T])
+	stream.idx = -1
+	var values chan T
+	var finished chan error
+	var v T
+	var ended bool
+	stop := make(chan struct{})
+	var stopOnce sync.Once
+
+	emit := func(v T) bool {
+		select {
+		case values <- v:
+			return true
+		case <-stop:
+			return false
+		}
+	}
+	start := func() {
+		values = make(chan T)
+		finished = make(chan error, 1)
+		go func() {
+			var err error
+			defer func() {
+				if r := recover(); r != nil {
+					err = recoverErr(r)
+				}
+				finished <- err
+				close(values)
+			}()
+			producer(emit)
+		}()
+	}
+
+	stream.next = func() bool {
+		if ended {
+			return false
+		}
+		if values == nil {
+			start()
+		}
+		var ok bool
+		if v, ok = <-values; ok {
+			stream.idx++
+			return true
+		}
+		ended = true
+		if err := <-finished; err != nil {
+			panic(err)
+		}
+		return false
+	}
+
+	stream.get = func() any {
+		return v
+	}
+	stream.getonrecover = func() RecoverFunc {
+		return nil
+	}
+	stream.geterr = func() error {
+		return nil
+	}
+	stream.close = func() error {
+		ended = true
+		stopOnce.Do(func() {
+			close(stop)
+		})
+		return nil
+	}
+	return stream
+}
+
+// ToChan returns a channel receiving the elements of this stream, which are sent by a goroutine.
+// the channel is closed when the stream ends, and a panic of the stream ends it too.
+// the goroutine exits only after all the elements are received, use ToChanCtx to stop it early.
+func (s *baseStream[T]) ToChan(buffer int) <-chan T {
+	out, _ := s.ToChanCtx(context.Background(), buffer)
+	return out
+}
+
+// ToChanCtx returns a channel receiving the elements of this stream, which are sent by a goroutine,
+// and a channel receiving the error of the stream, which are ctx.Err(), the first error of the upstream sources,
+// a PanicError or nil, once before the elements channel is closed.
+// the goroutine stops and closes the stream when the given context is done.
+func (s *baseStream[T]) ToChanCtx(ctx context.Context, buffer int) (<-chan T, <-chan error) {
+	if buffer < 0 {
+		buffer = 0
+	}
+	out := make(chan T, buffer)
+	errs := make(chan error, 1)
+	if s == nil {
+		close(errs)
+		close(out)
+		return out, errs
+	}
+
+	go func() {
+		var err error
+		defer func() {
+			if r := recover(); r != nil {
+				err = recoverErr(r)
+			}
+			s.close()
+			errs <- err
+			close(errs)
+			close(out)
+		}()
+
+		for s.next() {
+			select {
+			case out <- s.get().(T):
+			case <-ctx.Done():
+				err = ctx.Err()
+				return
+			}
+		}
+		err = s.geterr()
+	}()
+	return out, errs
+}
@@ -0,0 +1,177 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// checkGoroutines returns a function which fails the test if goroutines are left behind after the call.
+func checkGoroutines(t *testing.T) func() {
+	before := runtime.NumGoroutine()
+	return func() {
+		t.Helper()
+		deadline := time.Now().Add(time.Second)
+		for runtime.NumGoroutine() > before {
+			if time.Now().After(deadline) {
+				t.Errorf("%d goroutines leaked", runtime.NumGoroutine()-before)
+				return
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+}
+
+func produceInts(n int) func(emit func(int) bool) {
+	return func(emit func(int) bool) {
+		for i := 0; n < 0 || i < n; i++ {
+			if !emit(i) {
+				return
+			}
+		}
+	}
+}
+
+func TestFromProducer(t *testing.T) {
+	type testCase[T any] struct {
+		name string
+		s    Stream[T]
+		want []T
+	}
+	tests := []testCase[int]{
+		{
+			name: "empty",
+			s:    FromProducer(produceInts(0)),
+			want: []int{},
+		},
+		{
+			name: "finite",
+			s:    FromProducer(produceInts(3)),
+			want: []int{0, 1, 2},
+		},
+		{
+			name: "infinite",
+			s:    FromProducer(produceInts(-1)).Take(3),
+			want: []int{0, 1, 2},
+		},
+		{
+			name: "not pulled",
+			s:    FromProducer(produceInts(-1)).Take(0),
+			want: []int{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer checkGoroutines(t)()
+			if got := tt.s.Collect(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FromProducer() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromProducer_Panic(t *testing.T) {
+	defer checkGoroutines(t)()
+
+	got, err := CollectAsSafe[int](FromProducer(func(emit func(int) bool) {
+		emit(1)
+		panic(errBroken)
+	}).MapAny(func(ele int) any {
+		return ele
+	}))
+	if want := []int{1}; !reflect.DeepEqual(got, want) || !errors.Is(err, errBroken) {
+		t.Errorf("FromProducer() = %v, %v, want %v, %v", got, err, want, errBroken)
+	}
+}
+
+func TestStream_ToChan(t *testing.T) {
+	defer checkGoroutines(t)()
+
+	var got []int
+	for v := range FromVar(1, 2, 3).ToChan(1) {
+		got = append(got, v)
+	}
+	if want := []int{1, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ToChan() = %v, want %v", got, want)
+	}
+
+	// back to a stream
+	got = FromChan(FromVar(1, 2, 3).ToChan(0)).Map(func(ele int) int {
+		return ele * 10
+	}).Collect()
+	if want := []int{10, 20, 30}; !reflect.DeepEqual(got, want) {
+		t.Errorf("FromChan(ToChan()) = %v, want %v", got, want)
+	}
+}
+
+func TestStream_ToChanCtx(t *testing.T) {
+	t.Run("error", func(t *testing.T) {
+		defer checkGoroutines(t)()
+
+		out, errs := FromSource[int](newFailingSource(errBroken, 1, 2)).ToChanCtx(context.Background(), 0)
+		var got []int
+		for v := range out {
+			got = append(got, v)
+		}
+		if want := []int{1, 2}; !reflect.DeepEqual(got, want) {
+			t.Errorf("ToChanCtx() = %v, want %v", got, want)
+		}
+		if err := <-errs; err != errBroken {
+			t.Errorf("ToChanCtx() err = %v, want %v", err, errBroken)
+		}
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		defer checkGoroutines(t)()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		source := newClosingSource(1, 2, 3)
+		out, errs := FromSource[int](source).ToChanCtx(ctx, 0)
+		if v := <-out; v != 1 {
+			t.Errorf("ToChanCtx() = %v, want 1", v)
+		}
+		cancel()
+		if err := <-errs; err != context.Canceled {
+			t.Errorf("ToChanCtx() err = %v, want %v", err, context.Canceled)
+		}
+		for range out {
+		}
+		if closed := source.Closed(); closed != 1 {
+			t.Errorf("ToChanCtx() closed %d times, want 1", closed)
+		}
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		defer checkGoroutines(t)()
+
+		out, errs := Map(FromVar(1, 0), func(ele int) int {
+			return 1 / ele
+		}).ToChanCtx(context.Background(), 2)
+		var got []int
+		for v := range out {
+			got = append(got, v)
+		}
+		var panicErr *PanicError
+		if err := <-errs; !errors.As(err, &panicErr) || !reflect.DeepEqual(got, []int{1}) {
+			t.Errorf("ToChanCtx() = %v, %v, want [1], PanicError", got, err)
+		}
+	})
+
+	t.Run("producer", func(t *testing.T) {
+		defer checkGoroutines(t)()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		out, errs := FromProducer(produceInts(-1)).ToChanCtx(ctx, 0)
+		<-out
+		<-out
+		cancel()
+		for range out {
+		}
+		if err := <-errs; err != context.Canceled {
+			t.Errorf("ToChanCtx() err = %v, want %v", err, context.Canceled)
+		}
+	})
+}
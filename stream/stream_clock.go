@@ -0,0 +1,138 @@
+package stream
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is a source of time for the time based operations.
+// SystemClock is used by default, and ManualClock can be used in tests.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// NewTimer creates a Timer that sends the current time on its channel after at least duration d.
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer is a single event timer created by a Clock.
+type Timer interface {
+	// C returns the channel on which the time is delivered.
+	C() <-chan time.Time
+	// Stop prevents the Timer from firing, it returns false if the timer has already fired or been stopped.
+	Stop() bool
+}
+
+// SystemClock is the Clock of the time package.
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) NewTimer(d time.Duration) Timer {
+	return systemTimer{time.NewTimer(d)}
+}
+
+type systemTimer struct {
+	*time.Timer
+}
+
+func (t systemTimer) C() <-chan time.Time {
+	return t.Timer.C
+}
+
+// clockOrDefault returns SystemClock if the clock is nil.
+func clockOrDefault(clock Clock) Clock {
+	if clock == nil {
+		return SystemClock
+	}
+	return clock
+}
+
+// ManualClock is a Clock which advances only when Advance is called.
+// it makes the time based operations testable without sleeps.
+type ManualClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*manualTimer
+}
+
+// NewManualClock returns a ManualClock starting at the given time.
+func NewManualClock(now time.Time) *ManualClock {
+	return &ManualClock{now: now}
+}
+
+// Now returns the current time of the clock.
+func (c *ManualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// NewTimer creates a Timer which fires when the clock is advanced by d.
+// the timer fires immediately if d <= 0.
+func (c *ManualClock) NewTimer(d time.Duration) Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	timer := &manualTimer{
+		clock:    c,
+		deadline: c.now.Add(d),
+		c:        make(chan time.Time, 1),
+	}
+	if d <= 0 {
+		timer.c <- c.now
+		return timer
+	}
+	c.timers = append(c.timers, timer)
+	return timer
+}
+
+// Advance moves the clock forward by d and fires the timers which are due.
+func (c *ManualClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+	timers := c.timers[:0]
+	for _, timer := range c.timers {
+		if timer.deadline.After(c.now) {
+			timers = append(timers, timer)
+			continue
+		}
+		timer.c <- c.now
+	}
+	c.timers = timers
+}
+
+// Timers returns the number of timers which have not fired or been stopped.
+func (c *ManualClock) Timers() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.timers)
+}
+
+type manualTimer struct {
+	clock    *ManualClock
+	deadline time.Time
+	c        chan time.Time
+}
+
+func (t *manualTimer) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *manualTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	for i, timer := range t.clock.timers {
+		if timer == t {
+			t.clock.timers = append(t.clock.timers[:i], t.clock.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
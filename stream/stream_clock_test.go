@@ -0,0 +1,53 @@
+package stream
+
+import (
+	"testing"
+	"time"
+)
+
+func TestManualClock(t *testing.T) {
+	start := time.Unix(0, 0)
+	clock := NewManualClock(start)
+
+	timer1 := clock.NewTimer(time.Second)
+	timer2 := clock.NewTimer(2 * time.Second)
+	timer3 := clock.NewTimer(3 * time.Second)
+	if clock.Timers() != 3 {
+		t.Errorf("Timers() = %d, want %d", clock.Timers(), 3)
+	}
+
+	clock.Advance(1500 * time.Millisecond)
+	select {
+	case now := <-timer1.C():
+		if want := start.Add(1500 * time.Millisecond); !now.Equal(want) {
+			t.Errorf("timer fired at %v, want %v", now, want)
+		}
+	default:
+		t.Errorf("timer not fired")
+	}
+	select {
+	case <-timer2.C():
+		t.Errorf("timer fired early")
+	default:
+	}
+
+	if !timer3.Stop() {
+		t.Errorf("Stop() = false, want true")
+	}
+	if timer1.Stop() {
+		t.Errorf("Stop() = true for a fired timer")
+	}
+
+	clock.Advance(2 * time.Second)
+	<-timer2.C()
+	select {
+	case <-timer3.C():
+		t.Errorf("stopped timer fired")
+	default:
+	}
+	if clock.Timers() != 0 {
+		t.Errorf("Timers() = %d, want %d", clock.Timers(), 0)
+	}
+
+	<-clock.NewTimer(0).C()
+}
@@ -0,0 +1,81 @@
+package stream
+
+import (
+	"io"
+)
+
+//
+// resource lifecycle
+//
+// a Source which is an io.Closer is closed when the stream built on it is closed.
+// the terminal operations close the whole upstream chain when they return, even early or by a panic,
+// and the inner sources of FlatMapConcat are closed when they end.
+//
+
+// closeSource closes the source if it is an io.Closer, a Stream is an io.Closer.
+func closeSource[T any](source Source[T]) error {
+	if closer, ok := source.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// Close closes the upstream sources of this stream, which are io.Closer.
+// it is needed only when the stream is pulled by Next and Get without a terminal operation.
+func (s *baseStream[T]) Close() error {
+	if s == nil {
+		return nil
+	}
+	return s.close()
+}
+
+// OnComplete returns a stream that calls the callback once when this stream completes,
+// with the error of the stream when it ends, the error of a panic while pulling an element,
+// or nil when it is closed before the end.
+func (s *baseStream[T]) OnComplete(callback func(err error)) Stream[T] {
+	if s == nil {
+		return s
+	}
+
+	completed := false
+	complete := func(err error) {
+		if !completed {
+			completed = true
+			callback(err)
+		}
+	}
+	completeOnPanic := func() {
+		if r := recover(); r != nil {
+			complete(recoverErr(r))
+			panic(r)
+		}
+	}
+
+	completestream := new(baseStream[T])
+	completestream.idx = -1
+	completestream.next = func() bool {
+		defer completeOnPanic()
+		if s.next() {
+			completestream.idx++
+			return true
+		}
+		complete(s.geterr())
+		return false
+	}
+	completestream.get = func() any {
+		defer completeOnPanic()
+		return s.get()
+	}
+	completestream.getonrecover = func() RecoverFunc {
+		return s.getonrecover()
+	}
+	completestream.geterr = func() error {
+		return s.geterr()
+	}
+	completestream.close = func() error {
+		err := s.close()
+		complete(nil)
+		return err
+	}
+	return completestream
+}
@@ -0,0 +1,251 @@
+package stream
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// closingSource is a Source which records how many times it is closed.
+type closingSource[T any] struct {
+	failingSource[T]
+	closed int32
+	done   chan struct{}
+}
+
+func newClosingSource[T any](elements ...T) *closingSource[T] {
+	return &closingSource[T]{
+		failingSource: failingSource[T]{index: -1, elements: elements},
+		done:          make(chan struct{}),
+	}
+}
+
+func (c *closingSource[T]) Close() error {
+	if atomic.AddInt32(&c.closed, 1) == 1 {
+		close(c.done)
+	}
+	return nil
+}
+
+func (c *closingSource[T]) Closed() int {
+	return int(atomic.LoadInt32(&c.closed))
+}
+
+// waitClosed waits the source to be closed by another goroutine.
+func (c *closingSource[T]) waitClosed(t *testing.T) {
+	t.Helper()
+	select {
+	case <-c.done:
+	case <-time.After(time.Second):
+		t.Errorf("source is not closed")
+	}
+}
+
+func TestTerminal_Close(t *testing.T) {
+	tests := []struct {
+		name     string
+		terminal func(s Stream[int])
+	}{
+		{"ForEach", func(s Stream[int]) { s.ForEach(func(int) {}) }},
+		{"Collect", func(s Stream[int]) { s.Collect() }},
+		{"Take", func(s Stream[int]) { s.Take(1).Collect() }},
+		{"Find", func(s Stream[int]) { s.Find(func(ele int) bool { return ele == 1 }) }},
+		{"Any", func(s Stream[int]) { s.Any(func(ele int) bool { return ele == 1 }) }},
+		{"All", func(s Stream[int]) { s.All(func(ele int) bool { return ele > 1 }) }},
+		{"Count", func(s Stream[int]) { s.Count() }},
+		{"Fold", func(s Stream[int]) { Fold(s, "", func(acc string, ele int) string { return acc }) }},
+		{"CollectAs", func(s Stream[int]) { CollectAs[int](s.MapAny(func(ele int) any { return ele })) }},
+		{"CollectWith", func(s Stream[int]) { CollectWith(s, Counting[int]()) }},
+		{"Panic", func(s Stream[int]) {
+			defer func() {
+				recover()
+			}()
+			s.ForEach(func(int) { panic(errBroken) })
+		}},
+		{"Sorted", func(s Stream[int]) { s.Sorted(lessInt).Take(1).Collect() }},
+		{"ParallelMap", func(s Stream[int]) { s.ParallelMap(2, func(ele int) int { return ele }).Take(1).Collect() }},
+		{"Chunk", func(s Stream[int]) { Chunk(s, 2).Take(1).Collect() }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := newClosingSource(1, 2, 3)
+			tt.terminal(FromSource[int](source))
+			if closed := source.Closed(); closed != 1 {
+				t.Errorf("closed %d times, want 1", closed)
+			}
+		})
+	}
+}
+
+func TestStream_Close(t *testing.T) {
+	source := newClosingSource(1, 2, 3)
+	s := FromSource[int](source).Filter(func(ele int) bool {
+		return ele > 1
+	})
+	if !s.Next() || s.Get() != 2 {
+		t.Fatalf("Next() = false, want 2")
+	}
+	if err := s.Close(); err != nil || source.Closed() != 1 {
+		t.Errorf("Close() = %v, closed %d times, want 1", err, source.Closed())
+	}
+	// closed once
+	s.Close()
+	if closed := source.Closed(); closed != 1 {
+		t.Errorf("Close() closed %d times, want 1", closed)
+	}
+}
+
+func TestFlatMapConcat_Close(t *testing.T) {
+	var inners []*closingSource[int]
+	fmap := func(ele int) Source[int] {
+		inner := newClosingSource(ele, ele*10)
+		inners = append(inners, inner)
+		return inner
+	}
+
+	got := FromVar(1, 2, 3).FlatMapConcat(fmap).Take(3).Collect()
+	if want := []int{1, 10, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("FlatMapConcat() = %v, want %v", got, want)
+	}
+	if len(inners) != 2 {
+		t.Fatalf("FlatMapConcat() mapped %d sources, want 2", len(inners))
+	}
+	for i, inner := range inners {
+		if closed := inner.Closed(); closed != 1 {
+			t.Errorf("inner source %d closed %d times, want 1", i, closed)
+		}
+	}
+
+	inners = nil
+	FlatMap(FromVar(1, 2), fmap).Collect()
+	FromVar(3).FlatMapConcurrent(2, fmap).Collect()
+	for i, inner := range inners {
+		if closed := inner.Closed(); closed != 1 {
+			t.Errorf("inner source %d closed %d times, want 1", i, closed)
+		}
+	}
+}
+
+func TestZipWith_Close(t *testing.T) {
+	other := newClosingSource(10, 20, 30)
+	got := FromVar(1, 2, 3).ZipWith(other, func(a, b int) int {
+		return a + b
+	}).Take(1).Collect()
+	if want := []int{11}; !reflect.DeepEqual(got, want) || other.Closed() != 1 {
+		t.Errorf("ZipWith() = %v, closed %d times, want %v, 1", got, other.Closed(), want)
+	}
+
+	fallback := newClosingSource(1)
+	FromVar(1).OnErrorResume(fallback).Collect()
+	if closed := fallback.Closed(); closed != 1 {
+		t.Errorf("OnErrorResume() closed the fallback %d times, want 1", closed)
+	}
+}
+
+func TestSessionWindow_Close(t *testing.T) {
+	source := newClosingSource(1, 2, 3)
+	clock := NewManualClock(time.Unix(0, 0))
+
+	got := SessionWindow(FromSource[int](source), time.Second, clock).Take(1).Collect()
+	if want := [][]int{{1, 2, 3}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SessionWindow() = %v, want %v", got, want)
+	}
+	source.waitClosed(t)
+
+	// not pulled
+	source = newClosingSource(1, 2, 3)
+	WindowByTime(FromSource[int](source), time.Second, clock).Take(0).Collect()
+	source.waitClosed(t)
+}
+
+func TestSortedExternal_Close(t *testing.T) {
+	dir := spillDir(t)
+
+	got := SortedExternal(FromVar(5, 4, 3, 2, 1), lessInt, 2, GobCodec[int]()).Take(2).Collect()
+	if want := []int{1, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SortedExternal() = %v, want %v", got, want)
+	}
+	if files, _ := os.ReadDir(dir); len(files) != 0 {
+		t.Errorf("SortedExternal() left %d files", len(files))
+	}
+}
+
+func TestFromReaderLines_CloseEarly(t *testing.T) {
+	r := &closeRecorder{Reader: strings.NewReader("a\nb\n")}
+	FromReaderLines(r, WithCloseReader()).Take(1).Collect()
+	if r.closed != 1 {
+		t.Errorf("FromReaderLines() closed %d times, want 1", r.closed)
+	}
+}
+
+func TestStream_OnComplete(t *testing.T) {
+	type testCase struct {
+		name     string
+		terminal func(callback func(error)) []int
+		want     []int
+		wantErr  error
+	}
+	tests := []testCase{
+		{
+			name: "end",
+			terminal: func(callback func(error)) []int {
+				return FromVar(1, 2).OnComplete(callback).Collect()
+			},
+			want: []int{1, 2},
+		},
+		{
+			name: "error",
+			terminal: func(callback func(error)) []int {
+				return FromSource[int](newFailingSource(errBroken, 1)).OnComplete(callback).Collect()
+			},
+			want:    []int{1},
+			wantErr: errBroken,
+		},
+		{
+			name: "early exit",
+			terminal: func(callback func(error)) []int {
+				return FromVar(1, 2).OnComplete(callback).Take(1).Collect()
+			},
+			want: []int{1},
+		},
+		{
+			name: "panic",
+			terminal: func(callback func(error)) []int {
+				return Map(FromVar(1, 0), func(ele int) int {
+					return 1 / ele
+				}).OnComplete(callback).SkipOnError().Collect()
+			},
+			want: []int{1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int
+			var gotErr error
+			got := tt.terminal(func(err error) {
+				calls++
+				gotErr = err
+			})
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("OnComplete() = %v, want %v", got, tt.want)
+			}
+			if calls != 1 {
+				t.Errorf("OnComplete() called %d times, want 1", calls)
+			}
+			if tt.name == "panic" {
+				var panicErr *PanicError
+				if !errors.As(gotErr, &panicErr) {
+					t.Errorf("OnComplete() err = %v, want PanicError", gotErr)
+				}
+				return
+			}
+			if gotErr != tt.wantErr {
+				t.Errorf("OnComplete() err = %v, want %v", gotErr, tt.wantErr)
+			}
+		})
+	}
+}
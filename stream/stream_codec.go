@@ -0,0 +1,56 @@
+package stream
+
+import (
+	"encoding/gob"
+	"io"
+)
+
+// Codec creates encoders and decoders of the elements,
+// which are used to spill the elements to temporary files.
+type Codec[T any] interface {
+	NewEncoder(w io.Writer) Encoder[T]
+	NewDecoder(r io.Reader) Decoder[T]
+}
+
+// Encoder writes the elements to the underlying writer.
+type Encoder[T any] interface {
+	Encode(v T) error
+}
+
+// Decoder reads the elements from the underlying reader,
+// Decode returns io.EOF when there is no more element.
+type Decoder[T any] interface {
+	Decode() (T, error)
+}
+
+type gobCodec[T any] struct{}
+
+// GobCodec returns a Codec with encoding/gob, the elements should be encodable by gob.
+func GobCodec[T any]() Codec[T] {
+	return gobCodec[T]{}
+}
+
+func (gobCodec[T]) NewEncoder(w io.Writer) Encoder[T] {
+	return gobEncoder[T]{gob.NewEncoder(w)}
+}
+
+func (gobCodec[T]) NewDecoder(r io.Reader) Decoder[T] {
+	return gobDecoder[T]{gob.NewDecoder(r)}
+}
+
+type gobEncoder[T any] struct {
+	enc *gob.Encoder
+}
+
+func (e gobEncoder[T]) Encode(v T) error {
+	return e.enc.Encode(v)
+}
+
+type gobDecoder[T any] struct {
+	dec *gob.Decoder
+}
+
+func (d gobDecoder[T]) Decode() (v T, err error) {
+	err = d.dec.Decode(&v)
+	return
+}
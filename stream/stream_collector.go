@@ -0,0 +1,324 @@
+package stream
+
+import (
+	"strings"
+)
+
+// Collector is a composable reduction of the stream elements of type T
+// into a mutable accumulation of type A, which is finally transformed to a result of type R.
+//
+// Supply creates a new accumulation, Accumulate folds an element into the accumulation,
+// Combine merges two accumulations of the partial streams, and Finish transforms the accumulation to the result.
+type Collector[T, A, R any] interface {
+	Supply() A
+	Accumulate(acc A, ele T) A
+	Combine(left A, right A) A
+	Finish(acc A) R
+}
+
+type collector[T, A, R any] struct {
+	supplier    func() A
+	accumulator func(A, T) A
+	combiner    func(A, A) A
+	finisher    func(A) R
+}
+
+// NewCollector returns a Collector with the given functions.
+func NewCollector[T, A, R any](supplier func() A, accumulator func(acc A, ele T) A, combiner func(left A, right A) A, finisher func(acc A) R) Collector[T, A, R] {
+	return &collector[T, A, R]{
+		supplier:    supplier,
+		accumulator: accumulator,
+		combiner:    combiner,
+		finisher:    finisher,
+	}
+}
+
+func (c *collector[T, A, R]) Supply() A {
+	return c.supplier()
+}
+
+func (c *collector[T, A, R]) Accumulate(acc A, ele T) A {
+	return c.accumulator(acc, ele)
+}
+
+func (c *collector[T, A, R]) Combine(left A, right A) A {
+	return c.combiner(left, right)
+}
+
+func (c *collector[T, A, R]) Finish(acc A) R {
+	return c.finisher(acc)
+}
+
+func identity[A any](acc A) A {
+	return acc
+}
+
+// CollectWith performs a reduction on the elements of the stream with the given Collector.
+func CollectWith[T, A, R any](s Stream[T], c Collector[T, A, R]) R {
+	acc := c.Supply()
+	up := toBase[T](s)
+	if up == nil {
+		return c.Finish(acc)
+	}
+	if onerror := up.getonrecover(); onerror != nil {
+		defer onerror()
+	}
+	defer up.close()
+
+	for up.next() {
+		acc = c.Accumulate(acc, up.get().(T))
+	}
+	return c.Finish(acc)
+}
+
+//
+// collectors
+//
+
+// ToSlice returns a Collector that collects the elements into a slice.
+func ToSlice[T any]() Collector[T, []T, []T] {
+	return NewCollector(
+		func() []T {
+			return []T{}
+		},
+		func(acc []T, ele T) []T {
+			return append(acc, ele)
+		},
+		func(left []T, right []T) []T {
+			return append(left, right...)
+		},
+		identity[[]T],
+	)
+}
+
+// ToMap returns a Collector that collects the elements into a map,
+// the later element wins if the keys are duplicated.
+func ToMap[T any, K comparable, V any](keyf func(T) K, valuef func(T) V) Collector[T, map[K]V, map[K]V] {
+	return NewCollector(
+		func() map[K]V {
+			return map[K]V{}
+		},
+		func(acc map[K]V, ele T) map[K]V {
+			acc[keyf(ele)] = valuef(ele)
+			return acc
+		},
+		func(left map[K]V, right map[K]V) map[K]V {
+			for k, v := range right {
+				left[k] = v
+			}
+			return left
+		},
+		identity[map[K]V],
+	)
+}
+
+// ToSet returns a Collector that collects the distinct elements into a set.
+func ToSet[T comparable]() Collector[T, map[T]struct{}, map[T]struct{}] {
+	return NewCollector(
+		func() map[T]struct{} {
+			return map[T]struct{}{}
+		},
+		func(acc map[T]struct{}, ele T) map[T]struct{} {
+			acc[ele] = struct{}{}
+			return acc
+		},
+		func(left map[T]struct{}, right map[T]struct{}) map[T]struct{} {
+			for k := range right {
+				left[k] = struct{}{}
+			}
+			return left
+		},
+		identity[map[T]struct{}],
+	)
+}
+
+// Joining returns a Collector that concatenates the elements separated by sep.
+func Joining(sep string) Collector[string, []string, string] {
+	return NewCollector(
+		func() []string {
+			return []string{}
+		},
+		func(acc []string, ele string) []string {
+			return append(acc, ele)
+		},
+		func(left []string, right []string) []string {
+			return append(left, right...)
+		},
+		func(acc []string) string {
+			return strings.Join(acc, sep)
+		},
+	)
+}
+
+// Counting returns a Collector that counts the elements.
+func Counting[T any]() Collector[T, int, int] {
+	return NewCollector(
+		func() int {
+			return 0
+		},
+		func(acc int, ele T) int {
+			return acc + 1
+		},
+		func(left int, right int) int {
+			return left + right
+		},
+		identity[int],
+	)
+}
+
+// Summing returns a Collector that sums the numbers which the function returns for the elements.
+func Summing[T any, N Number](f func(T) N) Collector[T, N, N] {
+	return NewCollector(
+		func() N {
+			return 0
+		},
+		func(acc N, ele T) N {
+			return acc + f(ele)
+		},
+		func(left N, right N) N {
+			return left + right
+		},
+		identity[N],
+	)
+}
+
+// Average is the accumulation of Averaging, the sum and the count of the numbers.
+type Average struct {
+	Sum   float64
+	Count int
+}
+
+// Averaging returns a Collector that averages the numbers which the function returns for the elements.
+// the average of no element is 0.
+func Averaging[T any, N Number](f func(T) N) Collector[T, Average, float64] {
+	return NewCollector(
+		func() Average {
+			return Average{}
+		},
+		func(acc Average, ele T) Average {
+			return Average{acc.Sum + float64(f(ele)), acc.Count + 1}
+		},
+		func(left Average, right Average) Average {
+			return Average{left.Sum + right.Sum, left.Count + right.Count}
+		},
+		func(acc Average) float64 {
+			if acc.Count == 0 {
+				return 0
+			}
+			return acc.Sum / float64(acc.Count)
+		},
+	)
+}
+
+// minBy accumulates the smallest element in an Optional, which is empty if there is no element.
+func minBy[T any](less func(a T, b T) bool) Collector[T, Optional[T], Optional[T]] {
+	pick := func(left Optional[T], right Optional[T]) Optional[T] {
+		if !left.present || (right.present && less(right.value, left.value)) {
+			return right
+		}
+		return left
+	}
+	return NewCollector(
+		EmptyOptional[T],
+		func(acc Optional[T], ele T) Optional[T] {
+			return pick(acc, OptionalOf(ele))
+		},
+		pick,
+		identity[Optional[T]],
+	)
+}
+
+// MinBy returns a Collector that finds the first smallest element by less.
+// the result is an empty Optional if there is no element.
+func MinBy[T any](less func(a T, b T) bool) Collector[T, Optional[T], Optional[T]] {
+	return minBy(less)
+}
+
+// MaxBy returns a Collector that finds the first largest element by less.
+// the result is an empty Optional if there is no element.
+func MaxBy[T any](less func(a T, b T) bool) Collector[T, Optional[T], Optional[T]] {
+	return minBy(func(a T, b T) bool {
+		return less(b, a)
+	})
+}
+
+// Teeing returns a Collector that passes the elements to two collectors in one pass,
+// and merges their results with the merger. the accumulation is the Pair of the accumulations of the collectors.
+func Teeing[T, A1, R1, A2, R2, R any](c1 Collector[T, A1, R1], c2 Collector[T, A2, R2], merger func(R1, R2) R) Collector[T, Pair[A1, A2], R] {
+	return NewCollector(
+		func() Pair[A1, A2] {
+			return PairOf(c1.Supply(), c2.Supply())
+		},
+		func(acc Pair[A1, A2], ele T) Pair[A1, A2] {
+			return PairOf(c1.Accumulate(acc.First, ele), c2.Accumulate(acc.Second, ele))
+		},
+		func(left Pair[A1, A2], right Pair[A1, A2]) Pair[A1, A2] {
+			return PairOf(c1.Combine(left.First, right.First), c2.Combine(left.Second, right.Second))
+		},
+		func(acc Pair[A1, A2]) R {
+			return merger(c1.Finish(acc.First), c2.Finish(acc.Second))
+		},
+	)
+}
+
+// Mapping returns a Collector that applies the function to the elements before the downstream collector.
+func Mapping[T, U, A, R any](mapf func(T) U, downstream Collector[U, A, R]) Collector[T, A, R] {
+	return NewCollector(
+		downstream.Supply,
+		func(acc A, ele T) A {
+			return downstream.Accumulate(acc, mapf(ele))
+		},
+		downstream.Combine,
+		downstream.Finish,
+	)
+}
+
+// Filtering returns a Collector that passes only the elements matching the predicate to the downstream collector.
+func Filtering[T, A, R any](predicate func(T) bool, downstream Collector[T, A, R]) Collector[T, A, R] {
+	return NewCollector(
+		downstream.Supply,
+		func(acc A, ele T) A {
+			if predicate(ele) {
+				return downstream.Accumulate(acc, ele)
+			}
+			return acc
+		},
+		downstream.Combine,
+		downstream.Finish,
+	)
+}
+
+// GroupingBy returns a Collector that groups the elements by the key returned by keyf,
+// and collects each group with the downstream collector. see also GroupByCollectWith.
+func GroupingBy[T any, K comparable, A, R any](keyf func(T) K, downstream Collector[T, A, R]) Collector[T, map[K]A, map[K]R] {
+	return NewCollector(
+		func() map[K]A {
+			return map[K]A{}
+		},
+		func(acc map[K]A, ele T) map[K]A {
+			key := keyf(ele)
+			group, ok := acc[key]
+			if !ok {
+				group = downstream.Supply()
+			}
+			acc[key] = downstream.Accumulate(group, ele)
+			return acc
+		},
+		func(left map[K]A, right map[K]A) map[K]A {
+			for key, group := range right {
+				if leftgroup, ok := left[key]; ok {
+					group = downstream.Combine(leftgroup, group)
+				}
+				left[key] = group
+			}
+			return left
+		},
+		func(acc map[K]A) map[K]R {
+			result := make(map[K]R, len(acc))
+			for key, group := range acc {
+				result[key] = downstream.Finish(group)
+			}
+			return result
+		},
+	)
+}
@@ -0,0 +1,151 @@
+package stream
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestCollectWith(t *testing.T) {
+	if got, want := CollectWith(FromVar(1, 2, 3), ToSlice[int]()), []int{1, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ToSlice() = %v, want %v", got, want)
+	}
+	if got, want := CollectWith(FromVar[int](), ToSlice[int]()), []int{}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ToSlice() = %v, want %v", got, want)
+	}
+
+	got := CollectWith(FromSlice(groupItems), ToMap(itemGroup, func(ele groupItem) int {
+		return ele.Value
+	}))
+	if want := map[string]int{"a": 6, "b": 5, "c": 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ToMap() = %v, want %v", got, want)
+	}
+
+	set := CollectWith(FromVar("a", "b", "a"), ToSet[string]())
+	if want := map[string]struct{}{"a": {}, "b": {}}; !reflect.DeepEqual(set, want) {
+		t.Errorf("ToSet() = %v, want %v", set, want)
+	}
+
+	if got, want := CollectWith(FromVar("a", "b", "c"), Joining(", ")), "a, b, c"; got != want {
+		t.Errorf("Joining() = %v, want %v", got, want)
+	}
+	if got, want := CollectWith(FromVar("a", "b", "c"), Counting[string]()), 3; got != want {
+		t.Errorf("Counting() = %v, want %v", got, want)
+	}
+	if got, want := CollectWith(FromSlice(groupItems), Summing(func(ele groupItem) int {
+		return ele.Value
+	})), 21; got != want {
+		t.Errorf("Summing() = %v, want %v", got, want)
+	}
+	if got, want := CollectWith(FromVar(1, 2, 3, 4), Averaging(func(ele int) int {
+		return ele
+	})), 2.5; got != want {
+		t.Errorf("Averaging() = %v, want %v", got, want)
+	}
+	if got, want := CollectWith(FromVar[int](), Averaging(func(ele int) int {
+		return ele
+	})), 0.0; got != want {
+		t.Errorf("Averaging() = %v, want %v", got, want)
+	}
+}
+
+func TestMinByMaxBy(t *testing.T) {
+	less := func(a, b groupItem) bool {
+		return a.Group < b.Group
+	}
+	if got, want := CollectWith(FromSlice(groupItems), MinBy(less)), OptionalOf(groupItem{"a", 1}); got != want {
+		t.Errorf("MinBy() = %v, want %v", got, want)
+	}
+	if got, want := CollectWith(FromSlice(groupItems), MaxBy(less)), OptionalOf(groupItem{"c", 4}); got != want {
+		t.Errorf("MaxBy() = %v, want %v", got, want)
+	}
+	if got := CollectWith(FromSlice([]groupItem{}), MaxBy(less)); got.IsPresent() {
+		t.Errorf("MaxBy() = %v, want empty", got)
+	}
+	// a zero element found is told from no element
+	if got, want := CollectWith(FromVar(0, 1), MinBy(lessInt)), OptionalOf(0); got != want {
+		t.Errorf("MinBy() = %v, want %v", got, want)
+	}
+}
+
+func TestCollector_Accumulation(t *testing.T) {
+	// the accumulations can be declared and inspected by the callers
+	var avg Collector[int, Average, float64] = Averaging(func(ele int) int {
+		return ele
+	})
+	acc := avg.Combine(avg.Accumulate(avg.Supply(), 1), avg.Accumulate(avg.Supply(), 4))
+	if want := (Average{Sum: 5, Count: 2}); acc != want || avg.Finish(acc) != 2.5 {
+		t.Errorf("Averaging() accumulation = %v, want %v", acc, want)
+	}
+
+	var minc Collector[int, Optional[int], Optional[int]] = MinBy(lessInt)
+	if acc := minc.Supply(); acc.IsPresent() {
+		t.Errorf("MinBy() accumulation = %v, want empty", acc)
+	}
+	if acc := minc.Accumulate(minc.Accumulate(minc.Supply(), 3), 2); acc != OptionalOf(2) {
+		t.Errorf("MinBy() accumulation = %v, want %v", acc, OptionalOf(2))
+	}
+
+	var tee Collector[int, Pair[int, Optional[int]], int] = Teeing(Counting[int](), MinBy(lessInt), func(n int, m Optional[int]) int {
+		return n + m.OrElse(0)
+	})
+	if acc := tee.Accumulate(tee.Supply(), 5); acc != PairOf(1, OptionalOf(5)) || tee.Finish(acc) != 6 {
+		t.Errorf("Teeing() accumulation = %v, want %v", acc, PairOf(1, OptionalOf(5)))
+	}
+}
+
+func TestTeeing(t *testing.T) {
+	value := func(ele groupItem) int {
+		return ele.Value
+	}
+	got := CollectWith(FromSlice(groupItems), Teeing(Summing(value), Counting[groupItem](), func(sum int, count int) string {
+		return strconv.Itoa(sum) + "/" + strconv.Itoa(count)
+	}))
+	if want := "21/6"; got != want {
+		t.Errorf("Teeing() = %v, want %v", got, want)
+	}
+}
+
+func TestMappingFiltering(t *testing.T) {
+	names := Mapping(func(ele groupItem) string {
+		return ele.Group + strconv.Itoa(ele.Value)
+	}, Joining(","))
+	got := CollectWith(FromSlice(groupItems), Filtering(func(ele groupItem) bool {
+		return ele.Value%2 == 0
+	}, names))
+	if want := "b2,c4,a6"; got != want {
+		t.Errorf("Mapping() = %v, want %v", got, want)
+	}
+}
+
+func TestGroupingBy(t *testing.T) {
+	got := CollectWith(FromSlice(groupItems), GroupingBy(itemGroup, Mapping(func(ele groupItem) int {
+		return ele.Value
+	}, ToSlice[int]())))
+	want := map[string][]int{
+		"a": {1, 3, 6},
+		"b": {2, 5},
+		"c": {4},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GroupingBy() = %v, want %v", got, want)
+	}
+}
+
+func TestCollector_Combine(t *testing.T) {
+	c := GroupingBy(itemGroup, Counting[groupItem]())
+
+	left := c.Supply()
+	for _, ele := range groupItems[:3] {
+		left = c.Accumulate(left, ele)
+	}
+	right := c.Supply()
+	for _, ele := range groupItems[3:] {
+		right = c.Accumulate(right, ele)
+	}
+
+	got := c.Finish(c.Combine(left, right))
+	if want := map[string]int{"a": 3, "b": 2, "c": 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Combine() = %v, want %v", got, want)
+	}
+}
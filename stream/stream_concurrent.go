@@ -0,0 +1,410 @@
+package stream
+
+//
+// concurrent operations
+//
+// the upstream is pulled only by the goroutine consuming the stream,
+// and at most the given number of tasks run concurrently for the pulled elements.
+// each task delivers its result to a buffered channel and exits,
+// so no goroutine is left behind when the stream is not consumed to the end.
+// the inner sources of FlatMapConcurrent and FlatMapMerge are drained by their goroutines
+// through bounded channels as they produce, and the goroutines are stopped when the stream is closed.
+//
+
+type taskResult[R any] struct {
+	value R
+	err   error // recovered from a panic of the task
+}
+
+// taskPool runs a task for each element of the upstream with bounded concurrency.
+type taskPool[T, R any] struct {
+	up       *baseStream[T]
+	task     func(T) R
+	limit    int
+	ordered  bool
+	done     bool                 // upstream ended
+	pending  []chan taskResult[R] // ordered
+	results  chan taskResult[R]   // unordered
+	inflight int                  // unordered
+}
+
+func newTaskPool[T, R any](up *baseStream[T], limit int, ordered bool, task func(T) R) *taskPool[T, R] {
+	if limit < 1 {
+		limit = 1
+	}
+	pool := &taskPool[T, R]{
+		up:      up,
+		task:    task,
+		limit:   limit,
+		ordered: ordered,
+	}
+	if !ordered {
+		pool.results = make(chan taskResult[R], limit)
+	}
+	return pool
+}
+
+func (p *taskPool[T, R]) run(v T, result chan<- taskResult[R]) {
+	go func() {
+		var r taskResult[R]
+		defer func() {
+			if e := recover(); e != nil {
+				r.err = recoverErr(e)
+			}
+			result <- r
+		}()
+		r.value = p.task(v)
+	}()
+}
+
+// fill starts tasks for the upstream elements until the limit is reached.
+func (p *taskPool[T, R]) fill() {
+	for !p.done && p.running() < p.limit {
+		if !p.up.next() {
+			p.done = true
+			return
+		}
+		v := p.up.get().(T)
+		if p.ordered {
+			result := make(chan taskResult[R], 1)
+			p.pending = append(p.pending, result)
+			p.run(v, result)
+		} else {
+			p.inflight++
+			p.run(v, p.results)
+		}
+	}
+}
+
+func (p *taskPool[T, R]) running() int {
+	if p.ordered {
+		return len(p.pending)
+	}
+	return p.inflight
+}
+
+// next returns the result of the next task, in the upstream order if ordered,
+// otherwise in the completion order.
+// a panic of the task is raised again on the consuming goroutine.
+func (p *taskPool[T, R]) next() (value R, ok bool) {
+	p.fill()
+	if p.running() == 0 {
+		return value, false
+	}
+
+	var r taskResult[R]
+	if p.ordered {
+		r = <-p.pending[0]
+		p.pending = p.pending[1:]
+	} else {
+		r = <-p.results
+		p.inflight--
+	}
+	if r.err != nil {
+		panic(r.err)
+	}
+	return r.value, true
+}
+
+type parallelStream[T any] struct {
+	baseStream[T]
+	cur T
+}
+
+func parallelMap[T, U any](s Stream[T], workers int, ordered bool, mapf func(T) U) Stream[U] {
+	up := toBase[T](s)
+	if up == nil {
+		var nilstream *baseStream[U]
+		return nilstream
+	}
+
+	pool := newTaskPool(up, workers, ordered, mapf)
+	pmapstream := new(parallelStream[U])
+	pmapstream.idx = -1
+	pmapstream.next = func() bool {
+		v, ok := pool.next()
+		if ok {
+			pmapstream.idx++
+			pmapstream.cur = v
+		}
+		return ok
+	}
+	pmapstream.get = func() any {
+		return pmapstream.cur
+	}
+	pmapstream.getonrecover = func() RecoverFunc {
+		return up.getonrecover()
+	}
+	pmapstream.geterr = func() error {
+		return up.geterr()
+	}
+	pmapstream.close = func() error {
+		return up.close()
+	}
+	return pmapstream
+}
+
+// ParallelMap returns a stream consisting of the results of applying the given function to the elements of the stream.
+// the function is applied with at most workers goroutines concurrently, and the results keep the order of the stream.
+func ParallelMap[T, U any](s Stream[T], workers int, mapf func(T) U) Stream[U] {
+	return parallelMap(s, workers, true, mapf)
+}
+
+// ParallelMapUnordered returns a stream consisting of the results of applying the given function to the elements of the stream.
+// the function is applied with at most workers goroutines concurrently, and the results are emitted as they complete.
+func ParallelMapUnordered[T, U any](s Stream[T], workers int, mapf func(T) U) Stream[U] {
+	return parallelMap(s, workers, false, mapf)
+}
+
+// innerBuffer is the number of the elements an inner source is read ahead of the consumer in the order of the stream.
+const innerBuffer = 16
+
+// innerEvent is an element or the end of an inner source.
+type innerEvent[T any] struct {
+	value    T
+	ended    bool
+	err      error // error of the inner source, or recovered from a panic if panicked
+	panicked bool
+}
+
+// innerPool drains the inner sources of the upstream elements with bounded concurrency.
+type innerPool[T, U any] struct {
+	up       *baseStream[T]
+	fmap     func(T) Source[U]
+	limit    int
+	ordered  bool
+	done     bool                 // upstream ended
+	pending  []chan innerEvent[U] // ordered, a channel for each inner source
+	events   chan innerEvent[U]   // unordered
+	inflight int                  // unordered
+	stopped  chan struct{}        // closed by stop
+	halted   bool
+}
+
+func newInnerPool[T, U any](up *baseStream[T], limit int, ordered bool, fmap func(T) Source[U]) *innerPool[T, U] {
+	if limit < 1 {
+		limit = 1
+	}
+	pool := &innerPool[T, U]{
+		up:      up,
+		fmap:    fmap,
+		limit:   limit,
+		ordered: ordered,
+		stopped: make(chan struct{}),
+	}
+	if !ordered {
+		pool.events = make(chan innerEvent[U], limit)
+	}
+	return pool
+}
+
+// stop stops the goroutines, which close their inner sources.
+func (p *innerPool[T, U]) stop() {
+	if !p.halted {
+		p.halted = true
+		close(p.stopped)
+	}
+}
+
+// send delivers the event, it returns false if the pool is stopped.
+func (p *innerPool[T, U]) send(events chan<- innerEvent[U], event innerEvent[U]) bool {
+	select {
+	case <-p.stopped:
+		return false
+	default:
+	}
+	select {
+	case events <- event:
+		return true
+	case <-p.stopped:
+		return false
+	}
+}
+
+// drain forwards the elements of the inner source of v until it ends or the pool is stopped,
+// and closes the source.
+func (p *innerPool[T, U]) drain(v T, events chan<- innerEvent[U]) {
+	end := innerEvent[U]{ended: true}
+	defer func() {
+		if r := recover(); r != nil {
+			end.err = recoverErr(r)
+			end.panicked = true
+		}
+		p.send(events, end)
+	}()
+
+	source := p.fmap(v)
+	if source == nil {
+		return
+	}
+	defer closeSource(source)
+	for source.Next() {
+		if !p.send(events, innerEvent[U]{value: source.Get()}) {
+			return
+		}
+	}
+	end.err = sourceErr(source)
+}
+
+// fill starts draining the inner sources of the upstream elements until the limit is reached.
+func (p *innerPool[T, U]) fill() {
+	for !p.done && p.running() < p.limit {
+		if !p.up.next() {
+			p.done = true
+			return
+		}
+		v := p.up.get().(T)
+		if p.ordered {
+			events := make(chan innerEvent[U], innerBuffer)
+			p.pending = append(p.pending, events)
+			go p.drain(v, events)
+		} else {
+			p.inflight++
+			go p.drain(v, p.events)
+		}
+	}
+}
+
+func (p *innerPool[T, U]) running() int {
+	if p.ordered {
+		return len(p.pending)
+	}
+	return p.inflight
+}
+
+// next returns the next event, the inner sources follow the upstream order if ordered,
+// otherwise their elements are delivered as they are produced.
+// a panic of an inner source is raised again on the consuming goroutine.
+func (p *innerPool[T, U]) next() (event innerEvent[U], ok bool) {
+	p.fill()
+	if p.running() == 0 {
+		return event, false
+	}
+
+	if p.ordered {
+		event = <-p.pending[0]
+		if event.ended {
+			p.pending = p.pending[1:]
+		}
+	} else {
+		event = <-p.events
+		if event.ended {
+			p.inflight--
+		}
+	}
+	if event.panicked {
+		panic(event.err)
+	}
+	return event, true
+}
+
+type fmapConcurrentStream[T any] struct {
+	baseStream[T]
+	cur  T
+	err  error // error of the inner source
+	done bool
+}
+
+func flatMapConcurrent[T, U any](s Stream[T], concurrency int, ordered bool, fmap func(T) Source[U]) Stream[U] {
+	up := toBase[T](s)
+	if up == nil {
+		var nilstream *baseStream[U]
+		return nilstream
+	}
+
+	pool := newInnerPool(up, concurrency, ordered, fmap)
+	fmapstream := new(fmapConcurrentStream[U])
+	fmapstream.idx = -1
+	fmapstream.next = func() bool {
+		for !fmapstream.done {
+			event, ok := pool.next()
+			if !ok {
+				fmapstream.done = true
+				break
+			}
+			if event.ended {
+				if event.err != nil {
+					fmapstream.err = event.err
+					fmapstream.done = true
+					pool.stop()
+				}
+				continue
+			}
+			fmapstream.idx++
+			fmapstream.cur = event.value
+			return true
+		}
+		return false
+	}
+	fmapstream.get = func() any {
+		return fmapstream.cur
+	}
+	fmapstream.getonrecover = func() RecoverFunc {
+		return up.getonrecover()
+	}
+	fmapstream.geterr = func() error {
+		if fmapstream.err != nil {
+			return fmapstream.err
+		}
+		return up.geterr()
+	}
+	fmapstream.close = func() error {
+		fmapstream.done = true
+		pool.stop()
+		return up.close()
+	}
+	return fmapstream
+}
+
+// FlatMapConcurrent returns a stream consisting of the contents of the sources
+// produced by applying the provided mapping function to each element of the stream.
+// at most concurrency sources are produced and drained by their own goroutines concurrently,
+// and their contents are emitted in the order of the stream like FlatMapConcat.
+// a source is read ahead by a bounded buffer while the contents of the earlier sources are emitted.
+func FlatMapConcurrent[T, U any](s Stream[T], concurrency int, fmap func(T) Source[U]) Stream[U] {
+	return flatMapConcurrent(s, concurrency, true, fmap)
+}
+
+// FlatMapMerge returns a stream consisting of the contents of the sources
+// produced by applying the provided mapping function to each element of the stream.
+// at most concurrency sources are produced and drained by their own goroutines concurrently,
+// and the elements of the sources are emitted as soon as they are produced.
+func FlatMapMerge[T, U any](s Stream[T], concurrency int, fmap func(T) Source[U]) Stream[U] {
+	return flatMapConcurrent(s, concurrency, false, fmap)
+}
+
+// ParallelMap returns a stream consisting of the results of applying the given function to the elements of this stream
+// with at most workers goroutines, the results keep the order of this stream.
+func (s *baseStream[T]) ParallelMap(workers int, mapf func(T) T) Stream[T] {
+	if s == nil {
+		return s
+	}
+	return ParallelMap[T, T](s, workers, mapf)
+}
+
+// ParallelMapUnordered returns a stream consisting of the results of applying the given function to the elements of this stream
+// with at most workers goroutines, the results are emitted as they complete.
+func (s *baseStream[T]) ParallelMapUnordered(workers int, mapf func(T) T) Stream[T] {
+	if s == nil {
+		return s
+	}
+	return ParallelMapUnordered[T, T](s, workers, mapf)
+}
+
+// FlatMapConcurrent returns a stream consisting of the contents of the mapped sources,
+// which are drained by at most concurrency goroutines, in the order of this stream.
+func (s *baseStream[T]) FlatMapConcurrent(concurrency int, fmap func(T) Source[T]) Stream[T] {
+	if s == nil {
+		return s
+	}
+	return FlatMapConcurrent[T, T](s, concurrency, fmap)
+}
+
+// FlatMapMerge returns a stream consisting of the contents of the mapped sources,
+// which are drained by at most concurrency goroutines, in the order they are produced.
+func (s *baseStream[T]) FlatMapMerge(concurrency int, fmap func(T) Source[T]) Stream[T] {
+	if s == nil {
+		return s
+	}
+	return FlatMapMerge[T, T](s, concurrency, fmap)
+}
@@ -0,0 +1,161 @@
+package stream
+
+import (
+	"reflect"
+	"sort"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// concurrencyMeter records the max number of concurrent calls
+type concurrencyMeter struct {
+	running int32
+	max     int32
+}
+
+func (m *concurrencyMeter) enter() {
+	running := atomic.AddInt32(&m.running, 1)
+	for {
+		max := atomic.LoadInt32(&m.max)
+		if running <= max || atomic.CompareAndSwapInt32(&m.max, max, running) {
+			return
+		}
+	}
+}
+
+func (m *concurrencyMeter) leave() {
+	atomic.AddInt32(&m.running, -1)
+}
+
+func slowSquare(meter *concurrencyMeter) func(int) int {
+	return func(ele int) int {
+		meter.enter()
+		defer meter.leave()
+		// later elements complete earlier
+		time.Sleep(time.Duration(10-ele) * time.Millisecond)
+		return ele * ele
+	}
+}
+
+func TestParallelMap(t *testing.T) {
+	meter := &concurrencyMeter{}
+	got := ParallelMap(FromVar(1, 2, 3, 4, 5, 6, 7, 8), 3, slowSquare(meter)).Collect()
+
+	if want := []int{1, 4, 9, 16, 25, 36, 49, 64}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ParallelMap() = %v, want %v", got, want)
+	}
+	if meter.max > 3 {
+		t.Errorf("ParallelMap() ran %d workers, want at most %d", meter.max, 3)
+	}
+}
+
+func TestParallelMapUnordered(t *testing.T) {
+	meter := &concurrencyMeter{}
+	got := ParallelMapUnordered(FromVar(1, 2, 3, 4, 5, 6, 7, 8), 4, slowSquare(meter)).Collect()
+
+	sort.Ints(got)
+	if want := []int{1, 4, 9, 16, 25, 36, 49, 64}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ParallelMapUnordered() = %v, want %v", got, want)
+	}
+	if meter.max > 4 {
+		t.Errorf("ParallelMapUnordered() ran %d workers, want at most %d", meter.max, 4)
+	}
+}
+
+func TestStream_ParallelMap(t *testing.T) {
+	got := FromVar(1, 2, 3, 4, 5).ParallelMap(2, func(ele int) int {
+		return ele * 10
+	}).Take(3).Collect()
+
+	if want := []int{10, 20, 30}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ParallelMap() = %v, want %v", got, want)
+	}
+
+	got = FromVar[int]().ParallelMapUnordered(2, func(ele int) int {
+		return ele * 10
+	}).Collect()
+	if want := []int{}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ParallelMapUnordered() = %v, want %v", got, want)
+	}
+}
+
+func TestParallelMap_Panic(t *testing.T) {
+	got := ParallelMap(FromSlice([]*myStruct{{"a"}, nil, {"c"}}), 2, derefName).
+		Catch(func(err error) myStruct {
+			return myStruct{"?"}
+		}).Collect()
+
+	if want := []myStruct{{"a"}, {"?"}, {"c"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ParallelMap() = %v, want %v", got, want)
+	}
+}
+
+func repeatSource(ele int) Source[int] {
+	time.Sleep(time.Duration(5-ele) * time.Millisecond)
+	arr := make([]int, ele)
+	for i := range arr {
+		arr[i] = ele
+	}
+	return FromSlice(arr)
+}
+
+func TestFlatMapConcurrent(t *testing.T) {
+	got := FlatMapConcurrent(FromVar(1, 2, 3, 4), 2, repeatSource).Collect()
+
+	if want := []int{1, 2, 2, 3, 3, 3, 4, 4, 4, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("FlatMapConcurrent() = %v, want %v", got, want)
+	}
+
+	got, err := FromVar(1, 2, 3).FlatMapConcurrent(3, func(ele int) Source[int] {
+		if ele == 2 {
+			return newFailingSource(errBroken, 20, 21)
+		}
+		return FromVar(ele * 10)
+	}).CollectErr()
+	if want := []int{10, 20, 21}; !reflect.DeepEqual(got, want) || err != errBroken {
+		t.Errorf("FlatMapConcurrent() = %v, %v, want %v, %v", got, err, want, errBroken)
+	}
+}
+
+func TestFlatMapMerge(t *testing.T) {
+	got := FlatMapMerge(FromVar(1, 2, 3, 4), 4, repeatSource).Collect()
+
+	sort.Ints(got)
+	if want := []int{1, 2, 2, 3, 3, 3, 4, 4, 4, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("FlatMapMerge() = %v, want %v", got, want)
+	}
+
+	got = FromVar(1, 2, 3).FlatMapMerge(2, func(ele int) Source[int] {
+		return FromVar(ele, ele)
+	}).Take(2).Collect()
+	if len(got) != 2 {
+		t.Errorf("FlatMapMerge() = %v, want 2 elements", got)
+	}
+}
+
+func TestFlatMapConcurrent_Infinite(t *testing.T) {
+	defer checkGoroutines(t)()
+
+	repeat := func(v int) Source[int] {
+		return Repeat(v, -1)
+	}
+	got := FlatMapMerge(FromVar(1, 2), 2, repeat).Take(3).Collect()
+	if len(got) != 3 {
+		t.Errorf("FlatMapMerge() = %v, want 3 elements", got)
+	}
+	got = FlatMapConcurrent(FromVar(1, 2), 2, repeat).Take(3).Collect()
+	if want := []int{1, 1, 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("FlatMapConcurrent() = %v, want %v", got, want)
+	}
+
+	// an inner source is closed by its goroutine when the stream is closed
+	source := newClosingSource(1, 2, 3)
+	FlatMapConcurrent(FromVar(1, 2), 2, func(v int) Source[int] {
+		if v == 2 {
+			return FromSource[int](source)
+		}
+		return repeat(v)
+	}).Take(1).Collect()
+	source.waitClosed(t)
+}
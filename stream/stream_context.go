@@ -0,0 +1,70 @@
+package stream
+
+import (
+	"context"
+)
+
+type contextStream[T any] struct {
+	baseStream[T]
+	err error // error of the context
+}
+
+// WithContext returns a stream that ends when the given context is done.
+// the context is checked before pulling each element, and Err returns ctx.Err() once it is done.
+// it does not unblock an upstream waiting for an element, use FromChanCtx for channels.
+func (s *baseStream[T]) WithContext(ctx context.Context) Stream[T] {
+	if s == nil {
+		return s
+	}
+
+	ctxstream := new(contextStream[T])
+	ctxstream.idx = -1
+	ctxstream.next = func() bool {
+		if ctxstream.err != nil {
+			return false
+		}
+		if ctxstream.err = ctx.Err(); ctxstream.err != nil {
+			return false
+		}
+		if s.next() {
+			ctxstream.idx++
+			return true
+		}
+		return false
+	}
+	ctxstream.get = func() any {
+		return s.get()
+	}
+	ctxstream.getonrecover = func() RecoverFunc {
+		return s.getonrecover()
+	}
+	ctxstream.geterr = func() error {
+		if ctxstream.err != nil {
+			return ctxstream.err
+		}
+		return s.geterr()
+	}
+	ctxstream.close = func() error {
+		return s.close()
+	}
+	return ctxstream
+}
+
+// ForEachCtx performs an action for each element of this stream until the given context is done,
+// and returns ctx.Err() or the first error of the upstream sources.
+func (s *baseStream[T]) ForEachCtx(ctx context.Context, visit func(T)) error {
+	if s == nil {
+		return nil
+	}
+	return s.WithContext(ctx).ForEachErr(visit)
+}
+
+// CollectCtx returns a slice containing the elements of this stream until the given context is done,
+// and returns ctx.Err() or the first error of the upstream sources.
+// the elements collected before the error are returned with the error.
+func (s *baseStream[T]) CollectCtx(ctx context.Context) (target []T, err error) {
+	if s == nil {
+		return []T{}, nil
+	}
+	return s.WithContext(ctx).CollectErr()
+}
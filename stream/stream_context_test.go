@@ -0,0 +1,96 @@
+package stream
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestFromChanCtx(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	jobs := make(chan int)
+	go func() {
+		jobs <- 1
+		jobs <- 2
+		// stalls without closing the channel
+		cancel()
+	}()
+
+	got, err := FromChanCtx(ctx, jobs).Map(func(ele int) int {
+		return ele * 10
+	}).CollectErr()
+	if want := []int{10, 20}; !reflect.DeepEqual(got, want) {
+		t.Errorf("FromChanCtx() = %v, want %v", got, want)
+	}
+	if err != context.Canceled {
+		t.Errorf("FromChanCtx() err = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestFromChanCtx_Closed(t *testing.T) {
+	jobs := make(chan int, 3)
+	jobs <- 1
+	jobs <- 2
+	jobs <- 3
+	close(jobs)
+
+	got, err := FromChanCtx(context.Background(), jobs).CollectErr()
+	if want := []int{1, 2, 3}; !reflect.DeepEqual(got, want) || err != nil {
+		t.Errorf("FromChanCtx() = %v, %v, want %v", got, err, want)
+	}
+
+	got, err = FromChanCtx[int](context.Background(), nil).CollectErr()
+	if want := []int{}; !reflect.DeepEqual(got, want) || err != nil {
+		t.Errorf("FromChanCtx() = %v, %v, want %v", got, err, want)
+	}
+}
+
+func TestStream_WithContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	got, err := FromVar(1, 2, 3, 4, 5).OnEach(func(ele int) {
+		if ele == 3 {
+			cancel()
+		}
+	}).WithContext(ctx).CollectErr()
+	if want := []int{1, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("WithContext() = %v, want %v", got, want)
+	}
+	if err != context.Canceled {
+		t.Errorf("WithContext() err = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestStream_CollectCtx(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	got, err := FromVar(1, 2, 3).CollectCtx(ctx)
+	if want := []int{}; !reflect.DeepEqual(got, want) || err != context.Canceled {
+		t.Errorf("CollectCtx() = %v, %v, want %v, %v", got, err, want, context.Canceled)
+	}
+
+	got, err = FromVar(1, 2, 3).CollectCtx(context.Background())
+	if want := []int{1, 2, 3}; !reflect.DeepEqual(got, want) || err != nil {
+		t.Errorf("CollectCtx() = %v, %v, want %v", got, err, want)
+	}
+}
+
+func TestStream_ForEachCtx(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var visited []int
+	err := FromVar(1, 2, 3).ForEachCtx(ctx, func(ele int) {
+		visited = append(visited, ele)
+		if ele == 2 {
+			cancel()
+		}
+	})
+	if want := []int{1, 2}; !reflect.DeepEqual(visited, want) || err != context.Canceled {
+		t.Errorf("ForEachCtx() = %v, %v, want %v, %v", visited, err, want, context.Canceled)
+	}
+}
@@ -0,0 +1,253 @@
+package stream
+
+import (
+	"encoding"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// CSVOptions configures FromCSV and ToCSV, the zero value is a comma separated file with a header.
+type CSVOptions struct {
+	// Comma is the field delimiter, the default is ','.
+	Comma rune
+	// Comment is the comment character of FromCSV, lines beginning with it are ignored.
+	Comment rune
+	// NoHeader maps the columns to the fields by position instead of the header.
+	NoHeader bool
+	// TimeLayout is the layout of time.Time fields, the default is time.RFC3339.
+	TimeLayout string
+	// CloseReader closes the reader of FromCSV when the stream ends, if the reader is an io.Closer.
+	CloseReader bool
+}
+
+func (o CSVOptions) timeLayout() string {
+	if o.TimeLayout == "" {
+		return time.RFC3339
+	}
+	return o.TimeLayout
+}
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// parseCSV parses the value of a column into the field, an empty value is left as the zero value.
+func parseCSV(v reflect.Value, s string, layout string) error {
+	if s == "" {
+		return nil
+	}
+	if v.Type() == timeType {
+		t, err := time.Parse(layout, s)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	}
+	if v.CanAddr() && v.Addr().Type().Implements(textUnmarshalerType) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Pointer:
+		p := reflect.New(v.Type().Elem())
+		if err := parseCSV(p.Elem(), s, layout); err != nil {
+			return err
+		}
+		v.Set(p)
+	default:
+		return fmt.Errorf("unsupported type %v", v.Type())
+	}
+	return nil
+}
+
+// formatCSV formats the field into the value of a column, a nil pointer is formatted as an empty value.
+func formatCSV(v reflect.Value, layout string) (string, error) {
+	if v.Type() == timeType {
+		return v.Interface().(time.Time).Format(layout), nil
+	}
+	if v.Type().Implements(textMarshalerType) {
+		if v.Kind() == reflect.Pointer && v.IsNil() {
+			return "", nil
+		}
+		text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(text), err
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()), nil
+	case reflect.Pointer:
+		if v.IsNil() {
+			return "", nil
+		}
+		return formatCSV(v.Elem(), layout)
+	default:
+		return "", fmt.Errorf("unsupported type %v", v.Type())
+	}
+}
+
+// FromCSV build a Stream of the rows of given reader decoded into the struct T.
+// the columns are mapped to the fields by the header with `csv:"column"` tags,
+// or by the order of the fields if opts.NoHeader is set.
+// the fields of string, bool, integer, float, time.Time, encoding.TextUnmarshaler and pointers to them are supported.
+// an error of reading or decoding a row ends the stream and is reported by Err.
+func FromCSV[T any](r io.Reader, opts CSVOptions) Stream[T] {
+	cr := csv.NewReader(r)
+	if opts.Comma != 0 {
+		cr.Comma = opts.Comma
+	}
+	cr.Comment = opts.Comment
+	layout := opts.timeLayout()
+
+	var columns []columnField // mapped field of each column, index -1 if not mapped
+	readHeader := func() error {
+		var zero T
+		fields, err := columnFields(reflect.TypeOf(zero), "csv")
+		if err != nil {
+			return err
+		}
+		if opts.NoHeader {
+			columns = fields
+			return nil
+		}
+		header, err := cr.Read()
+		if err != nil {
+			return err
+		}
+		byColumn := map[string]int{}
+		for _, f := range fields {
+			byColumn[f.column] = f.index
+		}
+		columns = make([]columnField, len(header))
+		for i, column := range header {
+			index, ok := byColumn[column]
+			if !ok {
+				index = -1
+			}
+			columns[i] = columnField{column: column, index: index}
+		}
+		return nil
+	}
+
+	return FromSource[T](newReaderSource(r, readerOptions{close: opts.CloseReader}, func() (T, error) {
+		var v T
+		if columns == nil {
+			if err := readHeader(); err != nil {
+				return v, err
+			}
+		}
+		record, err := cr.Read()
+		if err != nil {
+			return v, err
+		}
+		line, _ := cr.FieldPos(0)
+		rv := reflect.ValueOf(&v).Elem()
+		for i, value := range record {
+			if i >= len(columns) || columns[i].index < 0 {
+				continue
+			}
+			if err := parseCSV(rv.Field(columns[i].index), value, layout); err != nil {
+				return v, fmt.Errorf("stream: csv line %d column %q: %w", line, columns[i].column, err)
+			}
+		}
+		return v, nil
+	}))
+}
+
+// ToCSV writes the elements of the stream to given writer as the rows of the struct T,
+// with a header of the columns unless opts.NoHeader is set. see FromCSV for the mapping of the columns.
+// it returns the first error of writing or encoding a row, or the error of the stream.
+func ToCSV[T any](s Stream[T], w io.Writer, opts CSVOptions) error {
+	up := toBase[T](s)
+	if up != nil {
+		if onerror := up.getonrecover(); onerror != nil {
+			defer onerror()
+		}
+		defer up.close()
+	}
+
+	var zero T
+	fields, err := columnFields(reflect.TypeOf(zero), "csv")
+	if err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	if opts.Comma != 0 {
+		cw.Comma = opts.Comma
+	}
+	layout := opts.timeLayout()
+
+	if !opts.NoHeader {
+		header := make([]string, len(fields))
+		for i, f := range fields {
+			header[i] = f.column
+		}
+		if err := cw.Write(header); err != nil {
+			return err
+		}
+	}
+
+	if up != nil {
+		record := make([]string, len(fields))
+		for up.next() {
+			rv := reflect.ValueOf(up.get().(T))
+			for i, f := range fields {
+				if record[i], err = formatCSV(rv.Field(f.index), layout); err != nil {
+					return fmt.Errorf("stream: csv column %q: %w", f.column, err)
+				}
+			}
+			if err := cw.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return err
+	}
+	if up != nil {
+		return up.geterr()
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package stream
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+type csvItem struct {
+	ID      int       `csv:"id"`
+	Name    string    `csv:"name"`
+	Score   float64   `csv:"score"`
+	Done    bool      `csv:"done"`
+	Created time.Time `csv:"created"`
+	Note    *string   `csv:"note"`
+	Ignored string    `csv:"-"`
+	hidden  string
+}
+
+func strptr(s string) *string {
+	return &s
+}
+
+func TestFromCSV(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	type testCase struct {
+		name  string
+		input string
+		opts  CSVOptions
+		want  []csvItem
+	}
+	tests := []testCase{
+		{
+			name:  "header",
+			input: "id,name,score,done,created,note\n1,a,1.5,true,2024-01-02T03:04:05Z,n\n2,b,,false,,\n",
+			want: []csvItem{
+				{ID: 1, Name: "a", Score: 1.5, Done: true, Created: created, Note: strptr("n")},
+				{ID: 2, Name: "b"},
+			},
+		},
+		{
+			name:  "header in other order with unknown columns",
+			input: "name,extra,id\na,x,1\n",
+			want:  []csvItem{{ID: 1, Name: "a"}},
+		},
+		{
+			name:  "positional",
+			input: "1;a;2;false;2024-01-02;\n",
+			opts:  CSVOptions{Comma: ';', NoHeader: true, TimeLayout: "2006-01-02"},
+			want:  []csvItem{{ID: 1, Name: "a", Score: 2, Created: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)}},
+		},
+		{
+			name:  "empty",
+			input: "",
+			want:  []csvItem{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FromCSV[csvItem](strings.NewReader(tt.input), tt.opts).CollectErr()
+			if !reflect.DeepEqual(got, tt.want) || err != nil {
+				t.Errorf("FromCSV() = %v, %v, want %v", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromCSV_Error(t *testing.T) {
+	got, err := FromCSV[csvItem](strings.NewReader("id,name\n1,a\nx,b\n3,c\n"), CSVOptions{}).CollectErr()
+	if want := []csvItem{{ID: 1, Name: "a"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("FromCSV() = %v, want %v", got, want)
+	}
+	if !errors.Is(err, strconv.ErrSyntax) || !strings.Contains(err.Error(), `line 3 column "id"`) {
+		t.Errorf("FromCSV() err = %v, want a syntax error of line 3", err)
+	}
+
+	_, err = FromCSV[int](strings.NewReader("1\n"), CSVOptions{}).CollectErr()
+	if err == nil {
+		t.Errorf("FromCSV() err = nil, want an error for a non struct")
+	}
+}
+
+func TestToCSV(t *testing.T) {
+	items := []csvItem{
+		{ID: 1, Name: "a,b", Score: 1.5, Done: true, Created: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), Note: strptr("n")},
+		{ID: 2, Name: "c", Ignored: "x"},
+	}
+	want := "id,name,score,done,created,note\n" +
+		"1,\"a,b\",1.5,true,2024-01-02T03:04:05Z,n\n" +
+		"2,c,0,false,0001-01-01T00:00:00Z,\n"
+
+	var buf bytes.Buffer
+	if err := ToCSV(FromSlice(items), &buf, CSVOptions{}); err != nil {
+		t.Fatalf("ToCSV() err = %v", err)
+	}
+	if got := buf.String(); got != want {
+		t.Errorf("ToCSV() = %q, want %q", got, want)
+	}
+
+	// round trip
+	got, err := FromCSV[csvItem](&buf, CSVOptions{}).CollectErr()
+	items[1].Ignored = ""
+	if !reflect.DeepEqual(got, items) || err != nil {
+		t.Errorf("FromCSV(ToCSV()) = %v, %v, want %v", got, err, items)
+	}
+}
+
+func TestToCSV_Error(t *testing.T) {
+	var buf bytes.Buffer
+	err := ToCSV(FromSource[csvItem](newFailingSource(errBroken, csvItem{ID: 1})), &buf, CSVOptions{NoHeader: true})
+	if err != errBroken {
+		t.Errorf("ToCSV() err = %v, want %v", err, errBroken)
+	}
+	if want := "1,,0,false,0001-01-01T00:00:00Z,\n"; buf.String() != want {
+		t.Errorf("ToCSV() = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestToCSV_Close(t *testing.T) {
+	// the stream is closed even if T cannot be written
+	source := newClosingSource(1, 2)
+	if err := ToCSV[int](FromSource[int](source), io.Discard, CSVOptions{}); err == nil {
+		t.Errorf("ToCSV() err = nil, want an error for a non struct")
+	}
+	if closed := source.Closed(); closed != 1 {
+		t.Errorf("ToCSV() closed %d times, want 1", closed)
+	}
+}
@@ -0,0 +1,227 @@
+package stream
+
+import (
+	"container/list"
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// DedupState remembers the keys of the elements which have been emitted by DistinctKeyWith.
+type DedupState[K comparable] interface {
+	// Add adds the key and returns true if the key has not been seen before.
+	Add(key K) bool
+}
+
+type hashDedup[K comparable] struct {
+	seen map[K]struct{}
+}
+
+// NewHashDedup returns a DedupState which remembers all the keys in a hash set.
+// it is exact but grows with the number of the distinct keys.
+func NewHashDedup[K comparable]() DedupState[K] {
+	return &hashDedup[K]{seen: map[K]struct{}{}}
+}
+
+func (d *hashDedup[K]) Add(key K) bool {
+	if _, ok := d.seen[key]; ok {
+		return false
+	}
+	d.seen[key] = struct{}{}
+	return true
+}
+
+type lruDedup[K comparable] struct {
+	capacity int
+	order    *list.List // the most recently seen key at front
+	seen     map[K]*list.Element
+}
+
+// NewLRUDedup returns a DedupState which remembers at most capacity recently seen keys.
+// a key seen again is refreshed, and the least recently seen key is forgotten when the capacity is exceeded,
+// so a duplicate is dropped only if it is seen again before capacity other keys are seen.
+func NewLRUDedup[K comparable](capacity int) DedupState[K] {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &lruDedup[K]{
+		capacity: capacity,
+		order:    list.New(),
+		seen:     map[K]*list.Element{},
+	}
+}
+
+func (d *lruDedup[K]) Add(key K) bool {
+	if e, ok := d.seen[key]; ok {
+		d.order.MoveToFront(e)
+		return false
+	}
+	d.seen[key] = d.order.PushFront(key)
+	if d.order.Len() > d.capacity {
+		oldest := d.order.Back()
+		d.order.Remove(oldest)
+		delete(d.seen, oldest.Value.(K))
+	}
+	return true
+}
+
+type bloomDedup[K comparable] struct {
+	bits   []uint64
+	m      uint64 // number of bits
+	hashes uint64 // number of hash functions
+	hash   func(K) uint64
+}
+
+// NewBloomDedup returns a DedupState with a Bloom filter sized for the expected number of the distinct keys
+// with the false positive rate, which uses a fixed amount of memory for unbounded streams.
+// a duplicate is always dropped, but a new key is also dropped with the false positive rate,
+// which grows when more keys than expected are added.
+// the keys are hashed by hash, which may be nil for the keys of the string, integer, float and bool kinds,
+// and NewBloomDedup panics if hash is nil for the other keys. use HashString and HashUint64 to hash a struct key.
+func NewBloomDedup[K comparable](expected int, fpRate float64, hash func(K) uint64) DedupState[K] {
+	if hash == nil {
+		hash = kindHash[K]()
+	}
+	if expected < 1 {
+		expected = 1
+	}
+	if fpRate <= 0 || fpRate >= 1 {
+		fpRate = 0.01
+	}
+	n := float64(expected)
+	m := math.Ceil(-n * math.Log(fpRate) / (math.Ln2 * math.Ln2))
+	k := math.Round(m / n * math.Ln2)
+	if k < 1 {
+		k = 1
+	}
+	return &bloomDedup[K]{
+		bits:   make([]uint64, (uint64(m)+63)/64),
+		m:      uint64(m),
+		hashes: uint64(k),
+		hash:   hash,
+	}
+}
+
+// HashString returns the 64-bit FNV-1a hash of the string.
+func HashString(s string) uint64 {
+	h := uint64(14695981039346656037)
+	for i := 0; i < len(s); i++ {
+		h ^= uint64(s[i])
+		h *= 1099511628211
+	}
+	return h
+}
+
+// HashUint64 returns a hash of the number whose bits are well mixed, by the finalizer of splitmix64.
+func HashUint64(v uint64) uint64 {
+	v ^= v >> 30
+	v *= 0xbf58476d1ce4e5b9
+	v ^= v >> 27
+	v *= 0x94d049bb133111eb
+	v ^= v >> 31
+	return v
+}
+
+// kindHash returns a hash of the keys of the basic kinds by their values.
+func kindHash[K comparable]() func(K) uint64 {
+	var zero K
+	t := reflect.TypeOf(zero)
+	if t == nil {
+		panic("stream: NewBloomDedup needs a hash of the interface keys")
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return func(key K) uint64 {
+			return HashString(reflect.ValueOf(key).String())
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(key K) uint64 {
+			return HashUint64(uint64(reflect.ValueOf(key).Int()))
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return func(key K) uint64 {
+			return HashUint64(reflect.ValueOf(key).Uint())
+		}
+	case reflect.Float32, reflect.Float64:
+		return func(key K) uint64 {
+			f := reflect.ValueOf(key).Float()
+			if f == 0 {
+				// -0 equals 0
+				f = 0
+			}
+			return HashUint64(math.Float64bits(f))
+		}
+	case reflect.Bool:
+		return func(key K) uint64 {
+			if reflect.ValueOf(key).Bool() {
+				return HashUint64(1)
+			}
+			return HashUint64(0)
+		}
+	}
+	panic(fmt.Sprintf("stream: NewBloomDedup needs a hash of the keys of %v", t))
+}
+
+// Add sets the bits of the key, the key is new if any of the bits has not been set.
+// the bit positions are derived from two halves of the 64-bit hash of the key.
+func (d *bloomDedup[K]) Add(key K) bool {
+	sum := d.hash(key)
+	h1, h2 := sum&0xffffffff, sum>>32|1
+
+	added := false
+	for i := uint64(0); i < d.hashes; i++ {
+		pos := (h1 + i*h2) % d.m
+		word, bit := pos/64, uint64(1)<<(pos%64)
+		if d.bits[word]&bit == 0 {
+			d.bits[word] |= bit
+			added = true
+		}
+	}
+	return added
+}
+
+// DistinctKey returns a stream consisting of the distinct elements of the stream by the key returned by keyf,
+// the first element of each key is emitted. unlike Distinct, the duplicates need not be consecutive.
+//
+//	[a, a, b, c, a] => [a, b, c]
+func DistinctKey[T any, K comparable](s Stream[T], keyf func(T) K) Stream[T] {
+	return DistinctKeyWith(s, keyf, NewHashDedup[K]())
+}
+
+// DistinctKeyWith returns a stream consisting of the distinct elements of the stream by the key returned by keyf,
+// the keys which have been seen are remembered by the state.
+// use NewLRUDedup or NewBloomDedup for an unbounded stream.
+func DistinctKeyWith[T any, K comparable](s Stream[T], keyf func(T) K, state DedupState[K]) Stream[T] {
+	up := toBase[T](s)
+	if up == nil {
+		var nilstream *baseStream[T]
+		return nilstream
+	}
+
+	distinctstream := new(distinctStream[T])
+	distinctstream.idx = -1
+	distinctstream.next = func() bool {
+		for up.next() {
+			v := up.get().(T)
+			if state.Add(keyf(v)) {
+				distinctstream.idx++
+				distinctstream.old = v
+				return true
+			}
+		}
+		return false
+	}
+	distinctstream.get = func() any {
+		return distinctstream.old
+	}
+	distinctstream.getonrecover = func() RecoverFunc {
+		return up.getonrecover()
+	}
+	distinctstream.geterr = func() error {
+		return up.geterr()
+	}
+	distinctstream.close = func() error {
+		return up.close()
+	}
+	return distinctstream
+}
@@ -0,0 +1,148 @@
+package stream
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestDistinctKey(t *testing.T) {
+	type testCase[T any] struct {
+		name string
+		s    Stream[T]
+		want []T
+	}
+	tests := []testCase[string]{
+		{
+			name: "empty",
+			s:    FromVar[string](),
+			want: []string{},
+		},
+		{
+			name: "not consecutive",
+			s:    FromVar("a", "a", "b", "c", "a"),
+			want: []string{"a", "b", "c"},
+		},
+		{
+			name: "distinct",
+			s:    FromVar("a", "b", "c"),
+			want: []string{"a", "b", "c"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DistinctKey(tt.s, func(ele string) string {
+				return ele
+			}).Collect()
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DistinctKey() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDistinctKey_FirstOfKey(t *testing.T) {
+	got := DistinctKey(FromVar("apple", "avocado", "banana", "blueberry", "cherry"), func(ele string) byte {
+		return ele[0]
+	}).Collect()
+	if want := []string{"apple", "banana", "cherry"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("DistinctKey() = %v, want %v", got, want)
+	}
+}
+
+func TestDistinctKeyWith_LRU(t *testing.T) {
+	type args struct {
+		capacity int
+	}
+	type testCase[T any] struct {
+		name string
+		s    Stream[T]
+		args args
+		want []T
+	}
+	tests := []testCase[int]{
+		{
+			name: "within capacity",
+			s:    FromVar(1, 2, 1, 2, 3),
+			args: args{2},
+			want: []int{1, 2, 3},
+		},
+		{
+			name: "forgotten",
+			s:    FromVar(1, 2, 3, 1),
+			args: args{2},
+			want: []int{1, 2, 3, 1},
+		},
+		{
+			name: "refreshed",
+			s:    FromVar(1, 2, 1, 3, 1, 2),
+			args: args{2},
+			want: []int{1, 2, 3, 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DistinctKeyWith(tt.s, func(ele int) int {
+				return ele
+			}, NewLRUDedup[int](tt.args.capacity)).Collect()
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DistinctKeyWith() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDistinctKeyWith_Bloom(t *testing.T) {
+	const n = 10000
+	nums := make([]int, 0, 2*n)
+	for i := 0; i < n; i++ {
+		nums = append(nums, i, i)
+	}
+
+	got := DistinctKeyWith(FromSlice(nums), func(ele int) int {
+		return ele
+	}, NewBloomDedup[int](n, 0.01, nil)).Collect()
+
+	seen := map[int]bool{}
+	for _, v := range got {
+		if seen[v] {
+			t.Fatalf("DistinctKeyWith() emitted %v twice", v)
+		}
+		seen[v] = true
+	}
+	// allow twice of the false positive rate
+	if dropped := n - len(got); dropped > n*2/100 {
+		t.Errorf("DistinctKeyWith() dropped %d of %d", dropped, n)
+	}
+}
+
+func TestNewBloomDedup_Hash(t *testing.T) {
+	// the keys have the same text with %v
+	type key struct {
+		A, B string
+	}
+	dedup := NewBloomDedup(100, 0.01, func(k key) uint64 {
+		return HashUint64(HashString(k.A)) ^ HashString(k.B)
+	})
+	if !dedup.Add(key{"a", " b"}) || !dedup.Add(key{"a ", "b"}) || dedup.Add(key{"a", " b"}) {
+		t.Errorf("Add() did not tell the keys apart")
+	}
+
+	// the keys of a named kind are hashed by their values
+	type id int
+	ids := NewBloomDedup[id](100, 0.01, nil)
+	if !ids.Add(1) || !ids.Add(2) || ids.Add(1) {
+		t.Errorf("Add() did not tell the ids apart")
+	}
+	floats := NewBloomDedup[float64](100, 0.01, nil)
+	if !floats.Add(0) || floats.Add(math.Copysign(0, -1)) {
+		t.Errorf("Add() told -0 from 0")
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("NewBloomDedup() of struct keys without a hash did not panic")
+		}
+	}()
+	NewBloomDedup[key](100, 0.01, nil)
+}
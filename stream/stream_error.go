@@ -0,0 +1,252 @@
+package stream
+
+import (
+	"fmt"
+)
+
+// PanicError is an error recovered from a panic while pulling an element of a stream.
+type PanicError struct {
+	Value any // the value given to panic
+	fatal bool
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("stream: panic: %v", e.Value)
+}
+
+// Unwrap returns the value given to panic if it is an error.
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// recoverErr converts a recovered value to an error.
+func recoverErr(r any) error {
+	if err, ok := r.(*PanicError); ok {
+		return err
+	}
+	return &PanicError{Value: r}
+}
+
+// sourceNext calls Next of the source, a panic of Next is raised again as a fatal PanicError
+// and failed is set, since the source has not advanced and cannot be pulled any more.
+func sourceNext[T any](source Source[T], failed *bool) bool {
+	defer func() {
+		if r := recover(); r != nil {
+			if err, ok := r.(*PanicError); ok {
+				// an element of a stream failed
+				panic(err)
+			}
+			*failed = true
+			panic(&PanicError{Value: r, fatal: true})
+		}
+	}()
+	return source.Next()
+}
+
+// pull pulls the next element from the stream and recovers from a panic.
+// more is false if the stream ended, err is not nil if the stream failed.
+// a panic while pulling an element fails only the element, and more is true,
+// but a panic of Next of a source ends the stream with the error.
+func (s *baseStream[T]) pull() (v T, more bool, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = recoverErr(r)
+			more = !err.(*PanicError).fatal
+		}
+	}()
+
+	if !s.next() {
+		return v, false, s.geterr()
+	}
+	return s.get().(T), true, nil
+}
+
+type catchStream[T any] struct {
+	baseStream[T]
+	cur  T
+	done bool
+}
+
+// Catch returns a stream that replaces an element which failed in the upstream with the result of the handler.
+// if the upstream ends with an error, the replacement is emitted as the last element.
+func (s *baseStream[T]) Catch(handler func(err error) T) Stream[T] {
+	if s == nil {
+		return s
+	}
+
+	catchstream := new(catchStream[T])
+	catchstream.idx = -1
+	catchstream.next = func() bool {
+		if catchstream.done {
+			return false
+		}
+		v, more, err := s.pull()
+		if err != nil {
+			v = handler(err)
+			catchstream.done = !more
+		} else if !more {
+			catchstream.done = true
+			return false
+		}
+		catchstream.idx++
+		catchstream.cur = v
+		return true
+	}
+	catchstream.get = func() any {
+		return catchstream.cur
+	}
+	catchstream.getonrecover = func() RecoverFunc {
+		return s.getonrecover()
+	}
+	catchstream.geterr = func() error {
+		return nil
+	}
+	catchstream.close = func() error {
+		return s.close()
+	}
+	return catchstream
+}
+
+// SkipOnError returns a stream that drops the elements which failed in the upstream.
+// if the upstream ends with an error, the stream ends without the error.
+func (s *baseStream[T]) SkipOnError() Stream[T] {
+	if s == nil {
+		return s
+	}
+
+	skipstream := new(catchStream[T])
+	skipstream.idx = -1
+	skipstream.next = func() bool {
+		for {
+			v, more, err := s.pull()
+			if !more {
+				return false
+			}
+			if err != nil {
+				continue
+			}
+			skipstream.idx++
+			skipstream.cur = v
+			return true
+		}
+	}
+	skipstream.get = func() any {
+		return skipstream.cur
+	}
+	skipstream.getonrecover = func() RecoverFunc {
+		return s.getonrecover()
+	}
+	skipstream.geterr = func() error {
+		return nil
+	}
+	skipstream.close = func() error {
+		return s.close()
+	}
+	return skipstream
+}
+
+// OnErrorReturn returns a stream that emits the given value and ends
+// when an element fails in the upstream or the upstream ends with an error.
+func (s *baseStream[T]) OnErrorReturn(value T) Stream[T] {
+	if s == nil {
+		return s
+	}
+
+	returnstream := new(catchStream[T])
+	returnstream.idx = -1
+	returnstream.next = func() bool {
+		if returnstream.done {
+			return false
+		}
+		v, more, err := s.pull()
+		if err != nil {
+			v = value
+			returnstream.done = true
+		} else if !more {
+			returnstream.done = true
+			return false
+		}
+		returnstream.idx++
+		returnstream.cur = v
+		return true
+	}
+	returnstream.get = func() any {
+		return returnstream.cur
+	}
+	returnstream.getonrecover = func() RecoverFunc {
+		return s.getonrecover()
+	}
+	returnstream.geterr = func() error {
+		return nil
+	}
+	returnstream.close = func() error {
+		return s.close()
+	}
+	return returnstream
+}
+
+type resumeStream[T any] struct {
+	catchStream[T]
+	fallback Source[T]
+	err      error // error of the upstream if there is no fallback
+}
+
+// OnErrorResume returns a stream that continues with the fallback source
+// when an element fails in the upstream or the upstream ends with an error.
+// the error of the fallback source is reported by Err,
+// or the error of the upstream if the fallback source is nil.
+func (s *baseStream[T]) OnErrorResume(fallback Source[T]) Stream[T] {
+	if s == nil {
+		return s
+	}
+
+	resumestream := new(resumeStream[T])
+	resumestream.idx = -1
+	resumestream.next = func() bool {
+		if resumestream.fallback == nil {
+			v, more, err := s.pull()
+			if err == nil {
+				if !more {
+					return false
+				}
+				resumestream.idx++
+				resumestream.cur = v
+				return true
+			}
+			if fallback == nil {
+				resumestream.err = err
+				return false
+			}
+			resumestream.fallback = fallback
+		}
+		if resumestream.fallback.Next() {
+			resumestream.idx++
+			resumestream.cur = resumestream.fallback.Get()
+			return true
+		}
+		return false
+	}
+	resumestream.get = func() any {
+		return resumestream.cur
+	}
+	resumestream.getonrecover = func() RecoverFunc {
+		return s.getonrecover()
+	}
+	resumestream.geterr = func() error {
+		if resumestream.fallback != nil {
+			return sourceErr(resumestream.fallback)
+		}
+		return resumestream.err
+	}
+	resumestream.close = func() error {
+		err := s.close()
+		if fallbackerr := closeSource(fallback); err == nil {
+			err = fallbackerr
+		}
+		return err
+	}
+	return resumestream
+}
@@ -0,0 +1,211 @@
+package stream
+
+import (
+	"errors"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func derefName(ele *myStruct) myStruct {
+	return myStruct{ele.Name}
+}
+
+func TestStream_Catch(t *testing.T) {
+	withnil := []*myStruct{{"a"}, nil, {"c"}}
+
+	type testCase[T any] struct {
+		name    string
+		s       Stream[T]
+		want    []T
+		wantErr error
+	}
+	tests := []testCase[myStruct]{
+		{
+			name: "no error",
+			s: Map(FromSlice([]*myStruct{{"a"}, {"b"}}), derefName).Catch(func(err error) myStruct {
+				return myStruct{"?"}
+			}),
+			want: []myStruct{{"a"}, {"b"}},
+		},
+		{
+			name: "panic in map",
+			s: Map(FromSlice(withnil), derefName).Catch(func(err error) myStruct {
+				return myStruct{"?"}
+			}),
+			want: []myStruct{{"a"}, {"?"}, {"c"}},
+		},
+		{
+			name: "panic in filter",
+			s: Map(FromSlice(withnil).Filter(func(ele *myStruct) bool {
+				return ele.Name != "c"
+			}).Catch(func(err error) *myStruct {
+				return &myStruct{"?"}
+			}), derefName),
+			want: []myStruct{{"a"}, {"?"}},
+		},
+		{
+			name: "upstream error",
+			s: FromSource[myStruct](newFailingSource(errBroken, myStruct{"a"})).Catch(func(err error) myStruct {
+				return myStruct{err.Error()}
+			}),
+			want: []myStruct{{"a"}, {"broken"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.s.CollectErr()
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Catch() = %v, want %v", got, tt.want)
+			}
+			if err != tt.wantErr {
+				t.Errorf("Catch() err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestStream_SkipOnError(t *testing.T) {
+	withnil := []*myStruct{nil, {"a"}, nil, nil, {"c"}, nil}
+
+	got, err := Map(FromSlice(withnil), derefName).SkipOnError().CollectErr()
+	if want := []myStruct{{"a"}, {"c"}}; !reflect.DeepEqual(got, want) || err != nil {
+		t.Errorf("SkipOnError() = %v, %v, want %v", got, err, want)
+	}
+
+	got, err = FromSource[myStruct](newFailingSource(errBroken, myStruct{"a"})).SkipOnError().CollectErr()
+	if want := []myStruct{{"a"}}; !reflect.DeepEqual(got, want) || err != nil {
+		t.Errorf("SkipOnError() = %v, %v, want %v", got, err, want)
+	}
+}
+
+func TestStream_OnErrorReturn(t *testing.T) {
+	withnil := []*myStruct{{"a"}, nil, {"c"}}
+
+	got, err := Map(FromSlice(withnil), derefName).OnErrorReturn(myStruct{"!"}).CollectErr()
+	if want := []myStruct{{"a"}, {"!"}}; !reflect.DeepEqual(got, want) || err != nil {
+		t.Errorf("OnErrorReturn() = %v, %v, want %v", got, err, want)
+	}
+
+	got, err = FromSource[myStruct](newFailingSource(errBroken, myStruct{"a"})).OnErrorReturn(myStruct{"!"}).CollectErr()
+	if want := []myStruct{{"a"}, {"!"}}; !reflect.DeepEqual(got, want) || err != nil {
+		t.Errorf("OnErrorReturn() = %v, %v, want %v", got, err, want)
+	}
+
+	got, err = FromVar(myStruct{"a"}).OnErrorReturn(myStruct{"!"}).CollectErr()
+	if want := []myStruct{{"a"}}; !reflect.DeepEqual(got, want) || err != nil {
+		t.Errorf("OnErrorReturn() = %v, %v, want %v", got, err, want)
+	}
+}
+
+func TestStream_OnErrorResume(t *testing.T) {
+	withnil := []*myStruct{{"a"}, nil, {"c"}}
+
+	got, err := Map(FromSlice(withnil), derefName).
+		OnErrorResume(FromVar(myStruct{"x"}, myStruct{"y"})).
+		CollectErr()
+	if want := []myStruct{{"a"}, {"x"}, {"y"}}; !reflect.DeepEqual(got, want) || err != nil {
+		t.Errorf("OnErrorResume() = %v, %v, want %v", got, err, want)
+	}
+
+	got, err = FromSource[myStruct](newFailingSource(errBroken, myStruct{"a"})).
+		OnErrorResume(newFailingSource(errBroken, myStruct{"x"})).
+		CollectErr()
+	if want := []myStruct{{"a"}, {"x"}}; !reflect.DeepEqual(got, want) || err != errBroken {
+		t.Errorf("OnErrorResume() = %v, %v, want %v, %v", got, err, want, errBroken)
+	}
+
+	// without a fallback the upstream error is kept
+	got, err = FromSource[myStruct](newFailingSource(errBroken, myStruct{"a"})).
+		OnErrorResume(nil).
+		CollectErr()
+	if want := []myStruct{{"a"}}; !reflect.DeepEqual(got, want) || err != errBroken {
+		t.Errorf("OnErrorResume() = %v, %v, want %v, %v", got, err, want, errBroken)
+	}
+}
+
+// panickySource is a Source whose Next always panics.
+type panickySource struct {
+	calls int
+}
+
+func (p *panickySource) Next() bool {
+	p.calls++
+	panic("broken source")
+}
+
+func (p *panickySource) Get() int {
+	return 0
+}
+
+func TestStream_PanickySource(t *testing.T) {
+	source := &panickySource{}
+	if got := FromSource[int](source).SkipOnError().Take(3).Collect(); len(got) != 0 {
+		t.Errorf("SkipOnError() = %v, want empty", got)
+	}
+	if source.calls != 1 {
+		t.Errorf("Next() called %d times, want 1", source.calls)
+	}
+
+	var errs []error
+	got := FromSource[int](&panickySource{}).Catch(func(err error) int {
+		errs = append(errs, err)
+		return -1
+	}).Collect()
+	if want := []int{-1}; !reflect.DeepEqual(got, want) || len(errs) != 1 {
+		t.Errorf("Catch() = %v, want %v", got, want)
+	}
+	var panicErr *PanicError
+	if !errors.As(errs[0], &panicErr) || panicErr.Value != "broken source" {
+		t.Errorf("Catch() error = %v, want the panic of the source", errs[0])
+	}
+
+	got, err := FromSource[int](&panickySource{}).OnErrorResume(FromVar(1)).CollectErr()
+	if want := []int{1}; !reflect.DeepEqual(got, want) || err != nil {
+		t.Errorf("OnErrorResume() = %v, %v, want %v", got, err, want)
+	}
+
+	// a failed element of a stream given as the source is not fatal
+	got = FromSource[int](Map(FromVar(1, 0, 2), func(ele int) int {
+		return 2 / ele
+	})).SkipOnError().Collect()
+	if want := []int{2, 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SkipOnError() = %v, want %v", got, want)
+	}
+}
+
+func TestCollectAsSafe(t *testing.T) {
+	s := FromVar(1, 2, 3).MapAny(func(ele int) any {
+		if ele == 3 {
+			return "3"
+		}
+		return ele
+	})
+
+	got, err := CollectAsSafe[int](s)
+	if want := []int{1, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("CollectAsSafe() = %v, want %v", got, want)
+	}
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) {
+		t.Fatalf("CollectAsSafe() err = %v, want PanicError", err)
+	}
+	var runtimeErr runtime.Error
+	if !errors.As(err, &runtimeErr) {
+		t.Errorf("CollectAsSafe() err = %v, want runtime.Error", err)
+	}
+
+	got, err = CollectAsSafe[int](FromSource[any](newFailingSource[any](errBroken, 1)))
+	if want := []int{1}; !reflect.DeepEqual(got, want) || err != errBroken {
+		t.Errorf("CollectAsSafe() = %v, %v, want %v, %v", got, err, want, errBroken)
+	}
+}
+
+func TestCollectToSafe(t *testing.T) {
+	s := FromVar[any](1, "2", 3)
+
+	got, err := CollectToSafe(s, []int{0})
+	if want := []int{0, 1}; !reflect.DeepEqual(got, want) || err == nil {
+		t.Errorf("CollectToSafe() = %v, %v, want %v and error", got, err, want)
+	}
+}
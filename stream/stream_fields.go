@@ -0,0 +1,35 @@
+package stream
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// columnField is an exported field of a struct mapped to a column of CSV or SQL,
+// the column is the tag of the field or the name of the field. a field tagged with "-" is not mapped.
+type columnField struct {
+	column string
+	index  int
+}
+
+func columnFields(t reflect.Type, tag string) ([]columnField, error) {
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("stream: %s needs a struct, got %v", tag, t)
+	}
+	var fields []columnField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		column := f.Tag.Get(tag)
+		if column == "-" {
+			continue
+		}
+		if column == "" {
+			column = f.Name
+		}
+		fields = append(fields, columnField{column: column, index: i})
+	}
+	return fields, nil
+}
@@ -0,0 +1,260 @@
+package stream
+
+//
+// type changing operations
+//
+// Go does not allow methods to have type parameters,
+// so the operations which change the element type of a stream are provided as functions.
+// they keep the whole pipeline statically typed unlike MapAny, ScanAny and so on.
+//
+
+// base returns the baseStream which the stream is built on.
+func (s *baseStream[T]) base() *baseStream[T] {
+	return s
+}
+
+// toBase returns the baseStream of the given source.
+// a source which is not a stream is wrapped with FromSource.
+func toBase[T any](source Source[T]) *baseStream[T] {
+	if source == nil {
+		return nil
+	}
+	if b, ok := source.(interface{ base() *baseStream[T] }); ok {
+		return b.base()
+	}
+	return FromSource(source).(*baseStream[T])
+}
+
+// Map returns a stream consisting of the results of applying the given function to the elements of the stream.
+func Map[T, U any](s Stream[T], mapf func(T) U) Stream[U] {
+	up := toBase[T](s)
+	if up == nil {
+		var nilstream *baseStream[U]
+		return nilstream
+	}
+
+	mapstream := new(baseStream[U])
+	mapstream.idx = -1
+	mapstream.next = func() bool {
+		if up.next() {
+			mapstream.idx++
+			return true
+		}
+		return false
+	}
+	mapstream.get = func() any {
+		return mapf(up.get().(T))
+	}
+	mapstream.getonrecover = func() RecoverFunc {
+		return up.getonrecover()
+	}
+	mapstream.geterr = func() error {
+		return up.geterr()
+	}
+	mapstream.close = func() error {
+		return up.close()
+	}
+	return mapstream
+}
+
+// MapIndex returns a stream consisting of the results of applying the given function to the elements of the stream.
+// the function is given the index of the element.
+func MapIndex[T, U any](s Stream[T], mapf func(int, T) U) Stream[U] {
+	up := toBase[T](s)
+	if up == nil {
+		var nilstream *baseStream[U]
+		return nilstream
+	}
+
+	mapstream := new(baseStream[U])
+	mapstream.idx = -1
+	mapstream.next = func() bool {
+		if up.next() {
+			mapstream.idx++
+			return true
+		}
+		return false
+	}
+	mapstream.get = func() any {
+		return mapf(mapstream.idx, up.get().(T))
+	}
+	mapstream.getonrecover = func() RecoverFunc {
+		return up.getonrecover()
+	}
+	mapstream.geterr = func() error {
+		return up.geterr()
+	}
+	mapstream.close = func() error {
+		return up.close()
+	}
+	return mapstream
+}
+
+// FlatMap returns a stream consisting of the results of
+// replacing each element of the stream with the contents of
+// a mapped source produced by applying the provided mapping function to each element.
+func FlatMap[T, U any](s Stream[T], fmap func(T) Source[U]) Stream[U] {
+	up := toBase[T](s)
+	if up == nil {
+		var nilstream *baseStream[U]
+		return nilstream
+	}
+
+	fmapstream := new(fmapStream[U])
+	fmapstream.idx = -1
+	fmapstream.next = func() bool {
+	loop:
+		if fmapstream.source != nil {
+			if fmapstream.source.Next() {
+				fmapstream.idx++
+				return true
+			}
+			fmapstream.err = sourceErr(fmapstream.source)
+			if err := fmapstream.closeSource(); fmapstream.err == nil {
+				fmapstream.err = err
+			}
+			if fmapstream.err != nil {
+				return false
+			}
+		}
+		for up.next() {
+			fmapstream.source = fmap(up.get().(T))
+			goto loop
+		}
+		return false
+	}
+	fmapstream.get = func() any {
+		return fmapstream.source.Get()
+	}
+	fmapstream.getonrecover = func() RecoverFunc {
+		return up.getonrecover()
+	}
+	fmapstream.geterr = func() error {
+		if fmapstream.err != nil {
+			return fmapstream.err
+		}
+		return up.geterr()
+	}
+	fmapstream.close = func() error {
+		err := fmapstream.closeSource()
+		if uperr := up.close(); err == nil {
+			err = uperr
+		}
+		return err
+	}
+	return fmapstream
+}
+
+// Scan returns a stream consisting of the accumlated results of applying the given function to the elements of the stream.
+//
+//	with source=[1, 2, 3, 4], init="" and func(acc, i) { acc + strconv.Itoa(i) } produces ["1", "12", "123", "1234"]
+func Scan[T, A any](s Stream[T], init A, accumf func(acc A, ele T) A) Stream[A] {
+	up := toBase[T](s)
+	if up == nil {
+		var nilstream *baseStream[A]
+		return nilstream
+	}
+
+	scanstream := new(scanStream[A])
+	scanstream.idx = -1
+	scanstream.acc = init
+	scanstream.next = func() bool {
+		if up.next() {
+			scanstream.idx++
+			return true
+		}
+		return false
+	}
+	scanstream.get = func() any {
+		scanstream.acc = accumf(scanstream.acc, up.get().(T))
+		return scanstream.acc
+	}
+	scanstream.getonrecover = func() RecoverFunc {
+		return up.getonrecover()
+	}
+	scanstream.geterr = func() error {
+		return up.geterr()
+	}
+	scanstream.close = func() error {
+		return up.close()
+	}
+	return scanstream
+}
+
+// ZipWith returns a stream consisting of applying the given function to the elements of the stream and another source.
+// the stream ends when either of them ends.
+func ZipWith[T, U, R any](s Stream[T], other Source[U], zipf func(T, U) R) Stream[R] {
+	up := toBase[T](s)
+	if up == nil || other == nil {
+		var nilstream *baseStream[R]
+		return nilstream
+	}
+
+	zipstream := new(baseStream[R])
+	zipstream.idx = -1
+	zipstream.next = func() bool {
+		if up.next() && other.Next() {
+			zipstream.idx++
+			return true
+		}
+		return false
+	}
+	zipstream.get = func() any {
+		return zipf(up.get().(T), other.Get())
+	}
+	zipstream.getonrecover = func() RecoverFunc {
+		return up.getonrecover()
+	}
+	zipstream.geterr = func() error {
+		if err := up.geterr(); err != nil {
+			return err
+		}
+		return sourceErr(other)
+	}
+	zipstream.close = func() error {
+		err := up.close()
+		if othererr := closeSource(other); err == nil {
+			err = othererr
+		}
+		return err
+	}
+	return zipstream
+}
+
+// Fold performs a reduction on the elements of the stream, using the provided initial value
+// and an accumulation function, and returns the reduced value.
+func Fold[T, A any](s Stream[T], init A, reducer func(acc A, ele T) A) (result A) {
+	up := toBase[T](s)
+	if up == nil {
+		return init
+	}
+	if onerror := up.getonrecover(); onerror != nil {
+		defer onerror()
+	}
+	defer up.close()
+
+	result = init
+	for up.next() {
+		result = reducer(result, up.get().(T))
+	}
+	return result
+}
+
+// FoldErr performs a reduction on the elements of the stream, using the provided initial value
+// and an accumulation function, and returns the reduced value and the first error of the upstream sources.
+func FoldErr[T, A any](s Stream[T], init A, reducer func(acc A, ele T) A) (result A, err error) {
+	up := toBase[T](s)
+	if up == nil {
+		return init, nil
+	}
+	if onerror := up.getonrecover(); onerror != nil {
+		defer onerror()
+	}
+	defer up.close()
+
+	result = init
+	for up.next() {
+		result = reducer(result, up.get().(T))
+	}
+	return result, up.geterr()
+}
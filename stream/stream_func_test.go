@@ -0,0 +1,199 @@
+package stream
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestMap(t *testing.T) {
+	arr := []myStruct{
+		{"a"},
+		{"bb"},
+		{"ccc"},
+	}
+
+	type testCase[T any, U any] struct {
+		name string
+		s    Stream[T]
+		mapf func(T) U
+		want []U
+	}
+	tests := []testCase[myStruct, int]{
+		{
+			name: "empty",
+			s:    FromSlice([]myStruct{}),
+			mapf: func(ele myStruct) int {
+				return len(ele.Name)
+			},
+			want: []int{},
+		},
+		{
+			name: "len",
+			s:    FromSlice(arr),
+			mapf: func(ele myStruct) int {
+				return len(ele.Name)
+			},
+			want: []int{1, 2, 3},
+		},
+		{
+			name: "filter len",
+			s: FromSlice(arr).Filter(func(ele myStruct) bool {
+				return len(ele.Name) != 2
+			}),
+			mapf: func(ele myStruct) int {
+				return len(ele.Name)
+			},
+			want: []int{1, 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Map(tt.s, tt.mapf).Collect(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Map() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMap_Chained(t *testing.T) {
+	names := Map(
+		Map(FromVar(1, 2, 3, 4), func(i int) myStruct {
+			return myStruct{strconv.Itoa(i)}
+		}).Filter(func(ele myStruct) bool {
+			return ele.Name != "2"
+		}),
+		func(ele myStruct) string {
+			return ele.Name + "!"
+		}).Collect()
+
+	want := []string{"1!", "3!", "4!"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("Map() = %v, want %v", names, want)
+	}
+}
+
+func TestMap_NilStream(t *testing.T) {
+	var s *baseStream[int]
+	if got := Map[int, string](s, strconv.Itoa).Collect(); !reflect.DeepEqual(got, []string{}) {
+		t.Errorf("Map() = %v, want empty", got)
+	}
+	if got := Map[int, string](nil, strconv.Itoa).Collect(); !reflect.DeepEqual(got, []string{}) {
+		t.Errorf("Map() = %v, want empty", got)
+	}
+}
+
+func TestMapIndex(t *testing.T) {
+	got := MapIndex(FromVar("a", "b", "c"), func(idx int, ele string) Indexed[string] {
+		return Indexed[string]{idx, ele}
+	}).Collect()
+
+	want := []Indexed[string]{{0, "a"}, {1, "b"}, {2, "c"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MapIndex() = %v, want %v", got, want)
+	}
+}
+
+func TestFlatMap(t *testing.T) {
+	type testCase[T any, U any] struct {
+		name string
+		s    Stream[T]
+		f    func(T) Source[U]
+		want []U
+	}
+	tests := []testCase[int, string]{
+		{
+			name: "empty",
+			s:    FromVar[int](),
+			f: func(i int) Source[string] {
+				return FromVar(strconv.Itoa(i))
+			},
+			want: []string{},
+		},
+		{
+			name: "repeat",
+			s:    FromVar(1, 2, 3),
+			f: func(i int) Source[string] {
+				var arr []string
+				for n := 0; n < i; n++ {
+					arr = append(arr, strconv.Itoa(i))
+				}
+				return FromSlice(arr)
+			},
+			want: []string{"1", "2", "2", "3", "3", "3"},
+		},
+		{
+			name: "empty inner",
+			s:    FromVar(1, 2, 3),
+			f: func(i int) Source[string] {
+				if i == 2 {
+					return FromVar[string]()
+				}
+				return FromVar(strconv.Itoa(i))
+			},
+			want: []string{"1", "3"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FlatMap(tt.s, tt.f).Collect(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FlatMap() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScan(t *testing.T) {
+	got := Scan(FromVar(1, 2, 3, 4), "", func(acc string, ele int) string {
+		return acc + strconv.Itoa(ele)
+	}).Collect()
+
+	want := []string{"1", "12", "123", "1234"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Scan() = %v, want %v", got, want)
+	}
+}
+
+func TestZipWith(t *testing.T) {
+	got := ZipWith[string, int](FromVar("a", "b", "c"), FromVar(1, 2), func(s string, i int) myStruct {
+		return myStruct{s + strconv.Itoa(i)}
+	}).Collect()
+
+	want := []myStruct{{"a1"}, {"b2"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ZipWith() = %v, want %v", got, want)
+	}
+}
+
+func TestFold(t *testing.T) {
+	type testCase[T any, A any] struct {
+		name string
+		s    Stream[T]
+		init A
+		want A
+	}
+	tests := []testCase[myStruct, int]{
+		{
+			name: "empty",
+			s:    FromSlice([]myStruct{}),
+			init: 10,
+			want: 10,
+		},
+		{
+			name: "sum of len",
+			s:    FromVar(myStruct{"a"}, myStruct{"bb"}, myStruct{"ccc"}),
+			init: 0,
+			want: 6,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Fold(tt.s, tt.init, func(acc int, ele myStruct) int {
+				return acc + len(ele.Name)
+			})
+			if got != tt.want {
+				t.Errorf("Fold() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
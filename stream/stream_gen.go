@@ -0,0 +1,154 @@
+package stream
+
+//
+// generative builders
+//
+// the streams are lazy and may be infinite, use Take or TakeWhile to end them.
+//
+
+// fromFunc build a Stream of the elements returned by next until it returns false.
+func fromFunc[T any](next func() (T, bool)) *baseStream[T] {
+	stream := new(baseStream[T])
+	stream.idx = -1
+	var v T
+	var done bool
+	stream.next = func() bool {
+		if done {
+			return false
+		}
+		var ok bool
+		if v, ok = next(); !ok {
+			done = true
+			return false
+		}
+		stream.idx++
+		return true
+	}
+
+	stream.get = func() any {
+		return v
+	}
+	stream.getonrecover = func() RecoverFunc {
+		return nil
+	}
+	stream.geterr = func() error {
+		return nil
+	}
+	stream.close = func() error {
+		done = true
+		return nil
+	}
+	return stream
+}
+
+// Range build a Stream of the numbers from start to end exclusive by step,
+// which counts down if step is negative, and is empty if step is zero.
+// the i-th number is start + i*step, so the float numbers do not accumulate errors.
+// the stream ends before a number which overflows the type.
+//
+//	Range(0, 10, 3) produces [0, 3, 6, 9]
+//	Range(5, 0, -2) produces [5, 3, 1]
+//	Range[uint8](250, 255, 3) produces [250, 253]
+func Range[T Number](start T, end T, step T) Stream[T] {
+	var i, prev T
+	return fromFunc(func() (v T, ok bool) {
+		v = start + i*step
+		if i > 0 && (step > 0 && v <= prev || step < 0 && v >= prev) {
+			// wrapped around the limit of the type
+			return v, false
+		}
+		if step > 0 && v < end || step < 0 && v > end {
+			i++
+			prev = v
+			return v, true
+		}
+		return v, false
+	})
+}
+
+// Iterate build an infinite Stream of seed, f(seed), f(f(seed)) and so on.
+func Iterate[T any](seed T, f func(T) T) Stream[T] {
+	v := seed
+	first := true
+	return fromFunc(func() (T, bool) {
+		if first {
+			first = false
+		} else {
+			v = f(v)
+		}
+		return v, true
+	})
+}
+
+// Generate build an infinite Stream of the values returned by the supplier.
+func Generate[T any](supplier func() T) Stream[T] {
+	return fromFunc(func() (T, bool) {
+		return supplier(), true
+	})
+}
+
+// Repeat build a Stream of the value repeated n times, or infinitely if n is negative.
+func Repeat[T any](v T, n int) Stream[T] {
+	count := 0
+	return fromFunc(func() (T, bool) {
+		if n >= 0 && count >= n {
+			return v, false
+		}
+		count++
+		return v, true
+	})
+}
+
+// Cycle returns an infinite Stream repeating the elements of the stream,
+// the elements are buffered in the first pass. the stream is empty if the stream is empty,
+// and ends after the first pass if the stream fails.
+func Cycle[T any](s Stream[T]) Stream[T] {
+	up := toBase[T](s)
+	if up == nil {
+		var nilstream *baseStream[T]
+		return nilstream
+	}
+
+	var buf []T
+	replay := -1 // index of buf to replay, -1 in the first pass
+	cyclestream := fromFunc(func() (v T, ok bool) {
+		if replay < 0 {
+			if up.next() {
+				v = up.get().(T)
+				buf = append(buf, v)
+				return v, true
+			}
+			if len(buf) == 0 || up.geterr() != nil {
+				return v, false
+			}
+			replay = 0
+		}
+		v = buf[replay]
+		replay = (replay + 1) % len(buf)
+		return v, true
+	})
+	cyclestream.getonrecover = func() RecoverFunc {
+		return up.getonrecover()
+	}
+	cyclestream.geterr = func() error {
+		return up.geterr()
+	}
+	end := cyclestream.close
+	cyclestream.close = func() error {
+		end()
+		return up.close()
+	}
+	return cyclestream
+}
+
+// Unfold build a Stream from the seed state, f returns an element and the next state,
+// and the stream ends when f returns false.
+//
+//	Unfold(1, func(s int) (int, int, bool) { return s, s * 2, s < 10 }) produces [1, 2, 4, 8]
+func Unfold[S, T any](seed S, f func(S) (T, S, bool)) Stream[T] {
+	state := seed
+	return fromFunc(func() (v T, ok bool) {
+		v, state, ok = f(state)
+		return v, ok
+	})
+}
@@ -0,0 +1,126 @@
+package stream
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRange(t *testing.T) {
+	type args struct {
+		start int
+		end   int
+		step  int
+	}
+	type testCase struct {
+		name string
+		args args
+		want []int
+	}
+	tests := []testCase{
+		{"up", args{0, 10, 3}, []int{0, 3, 6, 9}},
+		{"up exact", args{0, 9, 3}, []int{0, 3, 6}},
+		{"down", args{5, 0, -2}, []int{5, 3, 1}},
+		{"empty", args{5, 5, 1}, []int{}},
+		{"wrong direction", args{0, 5, -1}, []int{}},
+		{"zero step", args{0, 5, 0}, []int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Range(tt.args.start, tt.args.end, tt.args.step).Collect(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Range() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRange_Limits(t *testing.T) {
+	if got, want := Range[uint8](250, 255, 3).Collect(), []uint8{250, 253}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Range() = %v, want %v", got, want)
+	}
+	if got, want := Range[int8](120, 127, 5).Collect(), []int8{120, 125}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Range() = %v, want %v", got, want)
+	}
+	if got, want := Range[int8](-120, -128, -5).Collect(), []int8{-120, -125}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Range() = %v, want %v", got, want)
+	}
+	if got := Range[int8](-128, 127, 1).Count(); got != 255 {
+		t.Errorf("Range() count = %d, want 255", got)
+	}
+}
+
+func TestRange_Float(t *testing.T) {
+	got := Range(0.0, 1.0, 0.1).Collect()
+	if len(got) != 10 || got[9] != 0.0+9*0.1 {
+		t.Errorf("Range() = %v, want 10 numbers to 0.9", got)
+	}
+}
+
+func TestIterate(t *testing.T) {
+	got := Iterate(1, func(v int) int {
+		return v * 2
+	}).Take(5).Collect()
+	if want := []int{1, 2, 4, 8, 16}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Iterate() = %v, want %v", got, want)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	n := 0
+	got := Generate(func() int {
+		n++
+		return n
+	}).Take(3).Collect()
+	if want := []int{1, 2, 3}; !reflect.DeepEqual(got, want) || n != 3 {
+		t.Errorf("Generate() = %v called %d times, want %v", got, n, want)
+	}
+}
+
+func TestRepeat(t *testing.T) {
+	if got, want := Repeat("a", 3).Collect(), []string{"a", "a", "a"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Repeat() = %v, want %v", got, want)
+	}
+	if got, want := Repeat("a", 0).Collect(), []string{}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Repeat() = %v, want %v", got, want)
+	}
+	if got, want := Repeat("a", -1).Take(2).Collect(), []string{"a", "a"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Repeat() = %v, want %v", got, want)
+	}
+}
+
+func TestCycle(t *testing.T) {
+	source := newClosingSource(1, 2, 3)
+	got := Cycle(FromSource[int](source)).Take(7).Collect()
+	if want := []int{1, 2, 3, 1, 2, 3, 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Cycle() = %v, want %v", got, want)
+	}
+	if closed := source.Closed(); closed != 1 {
+		t.Errorf("Cycle() closed %d times, want 1", closed)
+	}
+
+	if got, want := Cycle(FromVar[int]()).Collect(), []int{}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Cycle() = %v, want %v", got, want)
+	}
+
+	// a partial pass of a failing stream is not replayed
+	got, err := Cycle(FromSource[int](newFailingSource(errBroken, 1, 2))).Take(5).CollectErr()
+	if want := []int{1, 2}; !reflect.DeepEqual(got, want) || err != errBroken {
+		t.Errorf("Cycle() = %v, %v, want %v, %v", got, err, want, errBroken)
+	}
+}
+
+func TestUnfold(t *testing.T) {
+	got := Unfold(1, func(s int) (int, int, bool) {
+		return s, s * 2, s < 10
+	}).Collect()
+	if want := []int{1, 2, 4, 8}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Unfold() = %v, want %v", got, want)
+	}
+
+	// fibonacci with the state of a pair
+	fib := Unfold([2]int{0, 1}, func(s [2]int) (int, [2]int, bool) {
+		return s[0], [2]int{s[1], s[0] + s[1]}, true
+	}).Take(8).Collect()
+	if want := []int{0, 1, 1, 2, 3, 5, 8, 13}; !reflect.DeepEqual(fib, want) {
+		t.Errorf("Unfold() = %v, want %v", fib, want)
+	}
+}
@@ -0,0 +1,92 @@
+package stream
+
+//
+// grouping operations
+//
+
+// GroupBy groups the elements of the stream by the key returned by keyf.
+// the elements of a group keep the order of the stream.
+func GroupBy[T any, K comparable](s Stream[T], keyf func(T) K) map[K][]T {
+	return GroupByFold(s, keyf, nil, func(group []T, ele T) []T {
+		return append(group, ele)
+	})
+}
+
+// GroupByCount counts the elements of the stream by the key returned by keyf.
+func GroupByCount[T any, K comparable](s Stream[T], keyf func(T) K) map[K]int {
+	return GroupByFold(s, keyf, 0, func(count int, ele T) int {
+		return count + 1
+	})
+}
+
+// GroupByFold groups the elements of the stream by the key returned by keyf,
+// and folds the elements of each group starting from init.
+func GroupByFold[T any, K comparable, A any](s Stream[T], keyf func(T) K, init A, reducer func(acc A, ele T) A) map[K]A {
+	result := map[K]A{}
+	up := toBase[T](s)
+	if up == nil {
+		return result
+	}
+	if onerror := up.getonrecover(); onerror != nil {
+		defer onerror()
+	}
+	defer up.close()
+
+	for up.next() {
+		v := up.get().(T)
+		key := keyf(v)
+		acc, ok := result[key]
+		if !ok {
+			acc = init
+		}
+		result[key] = reducer(acc, v)
+	}
+	return result
+}
+
+// GroupByCollect groups the elements of the stream by the key returned by keyf,
+// and collects each group with the downstream, which is given a stream of the elements of the group.
+// the groups are buffered in memory, use GroupByCollectWith to fold each group with a Collector instead.
+//
+//	GroupByCollect(s, keyf, func(group Stream[T]) int { return group.Count() })
+func GroupByCollect[T any, K comparable, R any](s Stream[T], keyf func(T) K, downstream func(group Stream[T]) R) map[K]R {
+	groups := GroupBy(s, keyf)
+	result := make(map[K]R, len(groups))
+	for key, group := range groups {
+		result[key] = downstream(FromSlice(group))
+	}
+	return result
+}
+
+// GroupByCollectWith groups the elements of the stream by the key returned by keyf,
+// and collects each group with the downstream Collector as the elements arrive, which is CollectWith with GroupingBy.
+//
+//	GroupByCollectWith(s, keyf, Counting[T]())
+func GroupByCollectWith[T any, K comparable, A, R any](s Stream[T], keyf func(T) K, downstream Collector[T, A, R]) map[K]R {
+	return CollectWith(s, GroupingBy(keyf, downstream))
+}
+
+// PartitionBy splits the elements of the stream into the elements which match the predicate and the others.
+// both keep the order of the stream.
+func PartitionBy[T any](s Stream[T], predicate func(T) bool) (matched []T, unmatched []T) {
+	matched = []T{}
+	unmatched = []T{}
+	up := toBase[T](s)
+	if up == nil {
+		return
+	}
+	if onerror := up.getonrecover(); onerror != nil {
+		defer onerror()
+	}
+	defer up.close()
+
+	for up.next() {
+		v := up.get().(T)
+		if predicate(v) {
+			matched = append(matched, v)
+		} else {
+			unmatched = append(unmatched, v)
+		}
+	}
+	return
+}
@@ -0,0 +1,106 @@
+package stream
+
+import (
+	"reflect"
+	"testing"
+)
+
+type groupItem struct {
+	Group string
+	Value int
+}
+
+var groupItems = []groupItem{
+	{"a", 1},
+	{"b", 2},
+	{"a", 3},
+	{"c", 4},
+	{"b", 5},
+	{"a", 6},
+}
+
+func itemGroup(ele groupItem) string {
+	return ele.Group
+}
+
+func TestGroupBy(t *testing.T) {
+	got := GroupBy(FromSlice(groupItems), itemGroup)
+	want := map[string][]groupItem{
+		"a": {{"a", 1}, {"a", 3}, {"a", 6}},
+		"b": {{"b", 2}, {"b", 5}},
+		"c": {{"c", 4}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GroupBy() = %v, want %v", got, want)
+	}
+
+	if got := GroupBy(FromSlice([]groupItem{}), itemGroup); len(got) != 0 {
+		t.Errorf("GroupBy() = %v, want empty", got)
+	}
+}
+
+func TestGroupByCount(t *testing.T) {
+	got := GroupByCount(FromSlice(groupItems), itemGroup)
+	if want := map[string]int{"a": 3, "b": 2, "c": 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GroupByCount() = %v, want %v", got, want)
+	}
+}
+
+func TestGroupByFold(t *testing.T) {
+	got := GroupByFold(FromSlice(groupItems), itemGroup, 100, func(acc int, ele groupItem) int {
+		return acc + ele.Value
+	})
+	if want := map[string]int{"a": 110, "b": 107, "c": 104}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GroupByFold() = %v, want %v", got, want)
+	}
+}
+
+func TestGroupByCollect(t *testing.T) {
+	got := GroupByCollect(FromSlice(groupItems), itemGroup, func(group Stream[groupItem]) []int {
+		return Map(group, func(ele groupItem) int {
+			return ele.Value
+		}).Filter(func(v int) bool {
+			return v > 1
+		}).Collect()
+	})
+	want := map[string][]int{
+		"a": {3, 6},
+		"b": {2, 5},
+		"c": {4},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GroupByCollect() = %v, want %v", got, want)
+	}
+}
+
+func TestGroupByCollectWith(t *testing.T) {
+	got := GroupByCollectWith(FromSlice(groupItems), itemGroup, Mapping(func(ele groupItem) int {
+		return ele.Value
+	}, Filtering(func(v int) bool {
+		return v > 1
+	}, ToSlice[int]())))
+	want := map[string][]int{
+		"a": {3, 6},
+		"b": {2, 5},
+		"c": {4},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GroupByCollectWith() = %v, want %v", got, want)
+	}
+
+	if got := GroupByCollectWith(FromVar[groupItem](), itemGroup, Counting[groupItem]()); len(got) != 0 {
+		t.Errorf("GroupByCollectWith() = %v, want empty", got)
+	}
+}
+
+func TestPartitionBy(t *testing.T) {
+	matched, unmatched := PartitionBy(FromVar(1, 2, 3, 4, 5), func(ele int) bool {
+		return ele%2 == 0
+	})
+	if want := []int{2, 4}; !reflect.DeepEqual(matched, want) {
+		t.Errorf("PartitionBy() matched = %v, want %v", matched, want)
+	}
+	if want := []int{1, 3, 5}; !reflect.DeepEqual(unmatched, want) {
+		t.Errorf("PartitionBy() unmatched = %v, want %v", unmatched, want)
+	}
+}
@@ -0,0 +1,191 @@
+package stream
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+)
+
+//
+// io builders
+//
+// the builders read the elements from an io.Reader through an ErrSource,
+// an error of the reader other than io.EOF ends the stream and is reported by Err.
+//
+
+// ReaderOption is an option of the builders reading from an io.Reader.
+type ReaderOption func(*readerOptions)
+
+type readerOptions struct {
+	close   bool
+	maxSize int
+}
+
+// WithCloseReader closes the reader when the stream ends or is closed, if the reader is an io.Closer.
+// an error of Close is reported by Err if the stream has not failed.
+func WithCloseReader() ReaderOption {
+	return func(o *readerOptions) {
+		o.close = true
+	}
+}
+
+// WithMaxLineSize limits the size of a line or a token without the delimiter,
+// a longer one fails the stream with bufio.ErrTooLong. lines are not limited by default.
+func WithMaxLineSize(n int) ReaderOption {
+	return func(o *readerOptions) {
+		o.maxSize = n
+	}
+}
+
+func newReaderOptions(opts []ReaderOption) readerOptions {
+	var o readerOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// readerSource is an ErrSource of the elements given by read, which returns io.EOF at the end.
+type readerSource[T any] struct {
+	read   func() (T, error)
+	closer io.Closer // closed when the source ends if not nil
+	cur    T
+	err    error
+	done   bool
+}
+
+func newReaderSource[T any](r io.Reader, o readerOptions, read func() (T, error)) *readerSource[T] {
+	source := &readerSource[T]{read: read}
+	if closer, ok := r.(io.Closer); ok && o.close {
+		source.closer = closer
+	}
+	return source
+}
+
+func (s *readerSource[T]) Next() bool {
+	if s.done {
+		return false
+	}
+	v, err := s.read()
+	if err != nil {
+		s.done = true
+		if err != io.EOF {
+			s.err = err
+		}
+		if err := s.Close(); s.err == nil {
+			s.err = err
+		}
+		return false
+	}
+	s.cur = v
+	return true
+}
+
+// Close ends the source, and closes the reader once if it is given to be closed.
+func (s *readerSource[T]) Close() error {
+	s.done = true
+	if s.closer == nil {
+		return nil
+	}
+	closer := s.closer
+	s.closer = nil
+	return closer.Close()
+}
+
+func (s *readerSource[T]) Get() T {
+	return s.cur
+}
+
+func (s *readerSource[T]) Err() error {
+	return s.err
+}
+
+// readToken reads a token until the delimiter and returns it without the delimiter.
+// the last token is returned without error if it is not empty, and io.EOF is returned after it.
+func readToken(br *bufio.Reader, delim byte, maxSize int) ([]byte, error) {
+	var token []byte
+	for {
+		frag, err := br.ReadSlice(delim)
+		token = append(token, frag...)
+		size := len(token)
+		if err == nil {
+			size-- // the delimiter
+		}
+		if maxSize > 0 && size > maxSize {
+			return nil, bufio.ErrTooLong
+		}
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		if err == nil {
+			return token[:len(token)-1], nil
+		}
+		if err == io.EOF && len(token) > 0 {
+			return token, nil
+		}
+		return nil, err
+	}
+}
+
+// FromReaderLines build a Stream of the lines of given reader without the line endings "\n" or "\r\n".
+// the lines are not limited in size unless WithMaxLineSize is given.
+func FromReaderLines(r io.Reader, opts ...ReaderOption) Stream[string] {
+	o := newReaderOptions(opts)
+	br := bufio.NewReader(r)
+	return FromSource[string](newReaderSource(r, o, func() (string, error) {
+		line, err := readToken(br, '\n', o.maxSize)
+		if err != nil {
+			return "", err
+		}
+		return string(bytes.TrimSuffix(line, []byte{'\r'})), nil
+	}))
+}
+
+// FromReaderDelim build a Stream of the tokens of given reader separated by the delimiter.
+// the tokens do not include the delimiter, and the tokens are not limited in size unless WithMaxLineSize is given.
+func FromReaderDelim(r io.Reader, delim byte, opts ...ReaderOption) Stream[string] {
+	o := newReaderOptions(opts)
+	br := bufio.NewReader(r)
+	return FromSource[string](newReaderSource(r, o, func() (string, error) {
+		token, err := readToken(br, delim, o.maxSize)
+		if err != nil {
+			return "", err
+		}
+		return string(token), nil
+	}))
+}
+
+// FromReaderChunks build a Stream of the chunks of n bytes of given reader,
+// the last chunk has less than n bytes if the size of the reader is not divided by n.
+// each chunk is a new slice which can be retained.
+func FromReaderChunks(r io.Reader, n int, opts ...ReaderOption) Stream[[]byte] {
+	if n < 1 {
+		n = 1
+	}
+	o := newReaderOptions(opts)
+	return FromSource[[]byte](newReaderSource(r, o, func() ([]byte, error) {
+		chunk := make([]byte, n)
+		read, err := io.ReadFull(r, chunk)
+		if err == io.ErrUnexpectedEOF {
+			err = nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		return chunk[:read], nil
+	}))
+}
+
+// FromScanner build a Stream of the tokens of given scanner, and the error of the scanner is reported by Err.
+// the scanner limits the size of a token, use Buffer of the scanner for long tokens.
+func FromScanner(scanner *bufio.Scanner) Stream[string] {
+	return FromSource[string](newReaderSource(nil, readerOptions{}, func() (string, error) {
+		if scanner.Scan() {
+			return scanner.Text(), nil
+		}
+		if err := scanner.Err(); err != nil {
+			return "", err
+		}
+		return "", io.EOF
+	}))
+}
@@ -0,0 +1,170 @@
+package stream
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+type closeRecorder struct {
+	io.Reader
+	closed int
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed++
+	return nil
+}
+
+func TestFromReaderLines(t *testing.T) {
+	type testCase struct {
+		name  string
+		input string
+		want  []string
+	}
+	tests := []testCase{
+		{
+			name:  "empty",
+			input: "",
+			want:  []string{},
+		},
+		{
+			name:  "lines",
+			input: "a\nbb\nccc\n",
+			want:  []string{"a", "bb", "ccc"},
+		},
+		{
+			name:  "no trailing newline",
+			input: "a\nbb",
+			want:  []string{"a", "bb"},
+		},
+		{
+			name:  "crlf and empty lines",
+			input: "a\r\n\r\nb\n\n",
+			want:  []string{"a", "", "b", ""},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FromReaderLines(strings.NewReader(tt.input)).CollectErr()
+			if !reflect.DeepEqual(got, tt.want) || err != nil {
+				t.Errorf("FromReaderLines() = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromReaderLines_LongLine(t *testing.T) {
+	long := strings.Repeat("x", 1<<20)
+	input := "a\n" + long + "\nb"
+
+	got, err := FromReaderLines(strings.NewReader(input)).CollectErr()
+	if err != nil || len(got) != 3 || got[1] != long {
+		t.Errorf("FromReaderLines() = %d lines, %v, want 3 lines", len(got), err)
+	}
+
+	got, err = FromReaderLines(strings.NewReader(input), WithMaxLineSize(1024)).CollectErr()
+	if want := []string{"a"}; !reflect.DeepEqual(got, want) || err != bufio.ErrTooLong {
+		t.Errorf("FromReaderLines() = %q, %v, want %q, %v", got, err, want, bufio.ErrTooLong)
+	}
+
+	got, err = FromReaderLines(strings.NewReader("ab\nabc\n"), WithMaxLineSize(3)).CollectErr()
+	if want := []string{"ab", "abc"}; !reflect.DeepEqual(got, want) || err != nil {
+		t.Errorf("FromReaderLines() = %q, %v, want %q", got, err, want)
+	}
+}
+
+func TestFromReaderLines_Error(t *testing.T) {
+	r := io.MultiReader(strings.NewReader("a\nb\n"), iotest.ErrReader(errBroken))
+
+	got, err := FromReaderLines(r).CollectErr()
+	if want := []string{"a", "b"}; !reflect.DeepEqual(got, want) || err != errBroken {
+		t.Errorf("FromReaderLines() = %q, %v, want %q, %v", got, err, want, errBroken)
+	}
+}
+
+func TestFromReaderLines_Close(t *testing.T) {
+	r := &closeRecorder{Reader: strings.NewReader("a\nb\n")}
+	FromReaderLines(r).Collect()
+	if r.closed != 0 {
+		t.Errorf("FromReaderLines() closed %d times, want 0", r.closed)
+	}
+
+	r = &closeRecorder{Reader: strings.NewReader("a\nb\n")}
+	s := FromReaderLines(r, WithCloseReader())
+	s.Collect()
+	s.Collect()
+	if r.closed != 1 {
+		t.Errorf("FromReaderLines() closed %d times, want 1", r.closed)
+	}
+}
+
+func TestFromReaderDelim(t *testing.T) {
+	got, err := FromReaderDelim(strings.NewReader("a,bb,,c"), ',').CollectErr()
+	if want := []string{"a", "bb", "", "c"}; !reflect.DeepEqual(got, want) || err != nil {
+		t.Errorf("FromReaderDelim() = %q, %v, want %q", got, err, want)
+	}
+
+	got, err = FromReaderDelim(iotest.OneByteReader(strings.NewReader("a\x00b\x00")), 0).CollectErr()
+	if want := []string{"a", "b"}; !reflect.DeepEqual(got, want) || err != nil {
+		t.Errorf("FromReaderDelim() = %q, %v, want %q", got, err, want)
+	}
+}
+
+func TestFromReaderChunks(t *testing.T) {
+	type testCase struct {
+		name  string
+		input string
+		n     int
+		want  [][]byte
+	}
+	tests := []testCase{
+		{
+			name:  "empty",
+			input: "",
+			n:     2,
+			want:  [][]byte{},
+		},
+		{
+			name:  "divided",
+			input: "abcd",
+			n:     2,
+			want:  [][]byte{[]byte("ab"), []byte("cd")},
+		},
+		{
+			name:  "partial",
+			input: "abcde",
+			n:     2,
+			want:  [][]byte{[]byte("ab"), []byte("cd"), []byte("e")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := iotest.HalfReader(strings.NewReader(tt.input))
+			got, err := FromReaderChunks(r, tt.n).CollectErr()
+			if !reflect.DeepEqual(got, tt.want) || err != nil {
+				t.Errorf("FromReaderChunks() = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromScanner(t *testing.T) {
+	scanner := bufio.NewScanner(strings.NewReader("a bb  ccc"))
+	scanner.Split(bufio.ScanWords)
+	got, err := FromScanner(scanner).CollectErr()
+	if want := []string{"a", "bb", "ccc"}; !reflect.DeepEqual(got, want) || err != nil {
+		t.Errorf("FromScanner() = %q, %v, want %q", got, err, want)
+	}
+
+	scanner = bufio.NewScanner(strings.NewReader("a\n" + strings.Repeat("x", 100) + "\n"))
+	scanner.Buffer(nil, 10)
+	got, err = FromScanner(scanner).CollectErr()
+	if want := []string{"a"}; !reflect.DeepEqual(got, want) || !errors.Is(err, bufio.ErrTooLong) {
+		t.Errorf("FromScanner() = %q, %v, want %q, %v", got, err, want, bufio.ErrTooLong)
+	}
+}
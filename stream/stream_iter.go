@@ -0,0 +1,131 @@
+//go:build go1.23
+
+package stream
+
+import (
+	"iter"
+)
+
+//
+// range-over-func iterators
+//
+// the adapters between streams and iter.Seq, which need Go 1.23 or later.
+//
+
+// FromPull build a Stream from given pull functions like the ones returned by iter.Pull,
+// next returns the next element and false at the end, and stop is called when the stream ends or is closed.
+func FromPull[T any](next func() (T, bool), stop func()) Stream[T] {
+	stream := new(baseStream[T])
+	stream.idx = -1
+	var v T
+	var done bool
+	finish := func() {
+		if !done {
+			done = true
+			if stop != nil {
+				stop()
+			}
+		}
+	}
+	stream.next = func() bool {
+		if done {
+			return false
+		}
+		var ok bool
+		if v, ok = next(); !ok {
+			finish()
+			return false
+		}
+		stream.idx++
+		return true
+	}
+
+	stream.get = func() any {
+		return v
+	}
+	stream.getonrecover = func() RecoverFunc {
+		return nil
+	}
+	stream.geterr = func() error {
+		return nil
+	}
+	stream.close = func() error {
+		finish()
+		return nil
+	}
+	return stream
+}
+
+// FromSeq build a Stream from given iterator, which is pulled by iter.Pull when the stream is pulled first.
+// the iterator is stopped when the stream is closed before the end.
+func FromSeq[T any](seq iter.Seq[T]) Stream[T] {
+	var next func() (T, bool)
+	var stop func()
+	return FromPull(func() (T, bool) {
+		if next == nil {
+			next, stop = iter.Pull(seq)
+		}
+		return next()
+	}, func() {
+		if stop != nil {
+			stop()
+		}
+	})
+}
+
+// FromSeq2 build a Stream of the results of applying zipf to the pairs of given iterator.
+func FromSeq2[K, V, R any](seq iter.Seq2[K, V], zipf func(K, V) R) Stream[R] {
+	return FromSeq(func(yield func(R) bool) {
+		for k, v := range seq {
+			if !yield(zipf(k, v)) {
+				return
+			}
+		}
+	})
+}
+
+// FromIndexedSeq build a Stream of Indexed from given iterator of the index and value pairs,
+// like slices.All.
+func FromIndexedSeq[V any](seq iter.Seq2[int, V]) Stream[Indexed[V]] {
+	return FromSeq2(seq, func(index int, value V) Indexed[V] {
+		return Indexed[V]{Index: index, Value: value}
+	})
+}
+
+// Seq returns an iterator over the elements of the stream, which is a terminal operation.
+// the stream is closed when the loop ends or breaks, and Err of the stream reports the error after the loop.
+//
+//	for v := range stream.Seq(s) {
+//		...
+//	}
+func Seq[T any](s Stream[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		up := toBase[T](s)
+		if up == nil {
+			return
+		}
+		if onerror := up.getonrecover(); onerror != nil {
+			defer onerror()
+		}
+		defer up.close()
+
+		for up.next() {
+			if !yield(up.get().(T)) {
+				return
+			}
+		}
+	}
+}
+
+// Seq2 returns an iterator over the index and element pairs of the stream like Seq.
+func Seq2[T any](s Stream[T]) iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		index := 0
+		for v := range Seq(s) {
+			if !yield(index, v) {
+				return
+			}
+			index++
+		}
+	}
+}
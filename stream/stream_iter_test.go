@@ -0,0 +1,132 @@
+//go:build go1.23
+
+package stream
+
+import (
+	"iter"
+	"maps"
+	"reflect"
+	"slices"
+	"sort"
+	"testing"
+)
+
+func TestFromSeq(t *testing.T) {
+	type testCase[T any] struct {
+		name string
+		s    Stream[T]
+		want []T
+	}
+	tests := []testCase[int]{
+		{
+			name: "empty",
+			s:    FromSeq(slices.Values([]int{})),
+			want: []int{},
+		},
+		{
+			name: "values",
+			s:    FromSeq(slices.Values([]int{1, 2, 3})),
+			want: []int{1, 2, 3},
+		},
+		{
+			name: "filter take",
+			s: FromSeq(slices.Values([]int{1, 2, 3, 4, 5})).Filter(func(ele int) bool {
+				return ele%2 == 1
+			}).Take(2),
+			want: []int{1, 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.s.Collect(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FromSeq() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromSeq_Stop(t *testing.T) {
+	var yielded []int
+	stopped := false
+	seq := func(yield func(int) bool) {
+		defer func() {
+			stopped = true
+		}()
+		for i := 0; ; i++ {
+			yielded = append(yielded, i)
+			if !yield(i) {
+				return
+			}
+		}
+	}
+
+	got := FromSeq(seq).Take(2).Collect()
+	if want := []int{0, 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("FromSeq() = %v, want %v", got, want)
+	}
+	if !stopped {
+		t.Errorf("FromSeq() did not stop the iterator")
+	}
+
+	// not pulled
+	stopped = false
+	yielded = nil
+	FromSeq(seq).Take(0).Collect()
+	if stopped || len(yielded) != 0 {
+		t.Errorf("FromSeq() ran the iterator %v, want not started", yielded)
+	}
+}
+
+func TestFromSeq2(t *testing.T) {
+	got := FromIndexedSeq(slices.All([]string{"a", "b"})).Collect()
+	if want := []Indexed[string]{{0, "a"}, {1, "b"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("FromIndexedSeq() = %v, want %v", got, want)
+	}
+
+	pairs := FromSeq2(maps.All(map[string]int{"a": 1, "b": 2}), func(k string, v int) string {
+		return k + "=" + string(rune('0'+v))
+	}).Collect()
+	sort.Strings(pairs)
+	if want := []string{"a=1", "b=2"}; !reflect.DeepEqual(pairs, want) {
+		t.Errorf("FromSeq2() = %v, want %v", pairs, want)
+	}
+}
+
+func TestSeq(t *testing.T) {
+	got := slices.Collect(Seq(FromVar(1, 2, 3).Map(func(ele int) int {
+		return ele * 10
+	})))
+	if want := []int{10, 20, 30}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Seq() = %v, want %v", got, want)
+	}
+
+	source := newClosingSource(1, 2, 3)
+	var visited []int
+	for v := range Seq(FromSource[int](source)) {
+		if v == 2 {
+			break
+		}
+		visited = append(visited, v)
+	}
+	if want := []int{1}; !reflect.DeepEqual(visited, want) || source.Closed() != 1 {
+		t.Errorf("Seq() = %v, closed %d times, want %v, 1", visited, source.Closed(), want)
+	}
+}
+
+func TestSeq2(t *testing.T) {
+	got := map[int]string{}
+	for i, v := range Seq2(FromVar("a", "b")) {
+		got[i] = v
+	}
+	if want := map[int]string{0: "a", 1: "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Seq2() = %v, want %v", got, want)
+	}
+}
+
+func TestFromPull(t *testing.T) {
+	next, stop := iter.Pull(slices.Values([]int{0, 1, 2}))
+	got := FromPull(next, stop).Collect()
+	if want := []int{0, 1, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("FromPull() = %v, want %v", got, want)
+	}
+}
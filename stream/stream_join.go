@@ -0,0 +1,258 @@
+package stream
+
+//
+// keyed joins
+//
+// the hash joins materialize the right stream into a hash table when the joined stream is pulled first,
+// and then pull the left stream lazily, so the smaller stream should be given as right.
+// the joined pairs are emitted in the order of the left stream, and the matches of a left element in the order of the right stream.
+//
+
+// joinEntry is an element of the right stream in the hash table.
+type joinEntry[R any] struct {
+	value   R
+	matched bool
+}
+
+// hashJoin joins the streams by a hash table of the right stream,
+// the unmatched left elements are emitted if outerLeft, and the unmatched right elements at the end if outerRight.
+func hashJoin[L, R any, K comparable](left Stream[L], right Stream[R], leftKey func(L) K, rightKey func(R) K, outerLeft bool, outerRight bool) Stream[Pair[Optional[L], Optional[R]]] {
+	upL := toBase[L](left)
+	upR := toBase[R](right)
+	if upL == nil || upR == nil {
+		var nilstream *baseStream[Pair[Optional[L], Optional[R]]]
+		return nilstream
+	}
+
+	table := make(map[K][]*joinEntry[R])
+	var entries []*joinEntry[R] // in the order of the right stream for outerRight
+	var built, rightClosed bool
+	var buildErr error
+	closeRight := func() error {
+		if rightClosed {
+			return nil
+		}
+		rightClosed = true
+		return upR.close()
+	}
+	build := func() {
+		for upR.next() {
+			e := &joinEntry[R]{value: upR.get().(R)}
+			k := rightKey(e.value)
+			table[k] = append(table[k], e)
+			if outerRight {
+				entries = append(entries, e)
+			}
+		}
+		buildErr = upR.geterr()
+		closeRight()
+	}
+
+	var curL L
+	var pending []*joinEntry[R] // the matches of curL
+	var leftDone, done bool
+	unmatched := 0
+	var cur Pair[Optional[L], Optional[R]]
+	joinstream := new(baseStream[Pair[Optional[L], Optional[R]]])
+	joinstream.idx = -1
+	joinstream.next = func() bool {
+		if done {
+			return false
+		}
+		if !built {
+			built = true
+			build()
+			if buildErr != nil {
+				done = true
+				return false
+			}
+		}
+		for {
+			if len(pending) > 0 {
+				e := pending[0]
+				pending = pending[1:]
+				e.matched = true
+				joinstream.idx++
+				cur = PairOf(OptionalOf(curL), OptionalOf(e.value))
+				return true
+			}
+			if leftDone {
+				break
+			}
+			if upL.next() {
+				curL = upL.get().(L)
+				pending = table[leftKey(curL)]
+				if len(pending) == 0 && outerLeft {
+					joinstream.idx++
+					cur = PairOf(OptionalOf(curL), EmptyOptional[R]())
+					return true
+				}
+				continue
+			}
+			leftDone = true
+			if upL.geterr() != nil {
+				// the matches of the right elements may be in the unread part of left
+				done = true
+				return false
+			}
+			if !outerRight {
+				break
+			}
+		}
+		for unmatched < len(entries) {
+			e := entries[unmatched]
+			unmatched++
+			if !e.matched {
+				joinstream.idx++
+				cur = PairOf(EmptyOptional[L](), OptionalOf(e.value))
+				return true
+			}
+		}
+		done = true
+		return false
+	}
+	joinstream.get = func() any {
+		return cur
+	}
+	joinstream.getonrecover = func() RecoverFunc {
+		if onerror := upL.getonrecover(); onerror != nil {
+			return onerror
+		}
+		return upR.getonrecover()
+	}
+	joinstream.geterr = func() error {
+		if buildErr != nil {
+			return buildErr
+		}
+		return upL.geterr()
+	}
+	joinstream.close = func() error {
+		err := upL.close()
+		if closeerr := closeRight(); err == nil {
+			err = closeerr
+		}
+		return err
+	}
+	return joinstream
+}
+
+// HashJoin returns a stream consisting of the pairs of the elements of left and right which have the same key,
+// right is materialized into a hash table and left is pulled lazily.
+//
+//	with left=[(1, a), (2, b)], right=[(1, x), (1, y), (3, z)] by the first values produces [((1, a), (1, x)), ((1, a), (1, y))]
+func HashJoin[L, R any, K comparable](left Stream[L], right Stream[R], leftKey func(L) K, rightKey func(R) K) Stream[Pair[L, R]] {
+	return Map(hashJoin(left, right, leftKey, rightKey, false, false), func(p Pair[Optional[L], Optional[R]]) Pair[L, R] {
+		return PairOf(p.First.value, p.Second.value)
+	})
+}
+
+// LeftJoin returns a stream consisting of the pairs of the elements of left and right which have the same key,
+// and the elements of left which have no match paired with an empty Optional.
+// right is materialized into a hash table and left is pulled lazily.
+func LeftJoin[L, R any, K comparable](left Stream[L], right Stream[R], leftKey func(L) K, rightKey func(R) K) Stream[Pair[L, Optional[R]]] {
+	return Map(hashJoin(left, right, leftKey, rightKey, true, false), func(p Pair[Optional[L], Optional[R]]) Pair[L, Optional[R]] {
+		return PairOf(p.First.value, p.Second)
+	})
+}
+
+// FullOuterJoin returns a stream consisting of the pairs of the elements of left and right which have the same key,
+// the elements of left which have no match paired with an empty Optional,
+// and at the end the elements of right which have no match paired with an empty Optional in the order of right.
+// right is materialized into a hash table and left is pulled lazily.
+func FullOuterJoin[L, R any, K comparable](left Stream[L], right Stream[R], leftKey func(L) K, rightKey func(R) K) Stream[Pair[Optional[L], Optional[R]]] {
+	return hashJoin(left, right, leftKey, rightKey, true, true)
+}
+
+// SortMergeJoin returns a stream consisting of the pairs of the elements of left and right which have the same key,
+// both of which must be sorted by the key in ascending order.
+// only the elements of right with the key of the current left element are kept in memory.
+//
+//	with left=[1, 2, 2, 4] and right=[2, 3, 4, 4] by themselves produces [(2, 2), (2, 2), (4, 4), (4, 4)]
+func SortMergeJoin[L, R any, K Ordered](left Stream[L], right Stream[R], leftKey func(L) K, rightKey func(R) K) Stream[Pair[L, R]] {
+	upL := toBase[L](left)
+	upR := toBase[R](right)
+	if upL == nil || upR == nil {
+		var nilstream *baseStream[Pair[L, R]]
+		return nilstream
+	}
+
+	var peek R // the next element of right
+	var peeked, rightDone, done bool
+	advance := func() {
+		if peeked = upR.next(); peeked {
+			peek = upR.get().(R)
+		} else {
+			rightDone = true
+		}
+	}
+
+	var curL L
+	var group []R // the elements of right with groupKey
+	var groupKey K
+	var grouped bool
+	var pending []R // the matches of curL
+	var cur Pair[L, R]
+	joinstream := new(baseStream[Pair[L, R]])
+	joinstream.idx = -1
+	joinstream.next = func() bool {
+		for !done {
+			if len(pending) > 0 {
+				joinstream.idx++
+				cur = PairOf(curL, pending[0])
+				pending = pending[1:]
+				return true
+			}
+			if !upL.next() {
+				done = true
+				break
+			}
+			curL = upL.get().(L)
+			k := leftKey(curL)
+			if !grouped || k != groupKey {
+				group = group[:0]
+				if !peeked && !rightDone {
+					advance()
+				}
+				for peeked && rightKey(peek) < k {
+					advance()
+				}
+				for peeked && rightKey(peek) == k {
+					group = append(group, peek)
+					advance()
+				}
+				if rightDone && (len(group) == 0 || upR.geterr() != nil) {
+					// no more matches for the following left elements
+					done = true
+					break
+				}
+				groupKey = k
+				grouped = true
+			}
+			pending = group
+		}
+		return false
+	}
+	joinstream.get = func() any {
+		return cur
+	}
+	joinstream.getonrecover = func() RecoverFunc {
+		if onerror := upL.getonrecover(); onerror != nil {
+			return onerror
+		}
+		return upR.getonrecover()
+	}
+	joinstream.geterr = func() error {
+		if err := upL.geterr(); err != nil {
+			return err
+		}
+		return upR.geterr()
+	}
+	joinstream.close = func() error {
+		err := upL.close()
+		if closeerr := upR.close(); err == nil {
+			err = closeerr
+		}
+		return err
+	}
+	return joinstream
+}
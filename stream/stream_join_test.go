@@ -0,0 +1,188 @@
+package stream
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type joinUser struct {
+	Id   int
+	Name string
+}
+
+type joinTodo struct {
+	UserId int
+	Title  string
+}
+
+func todoUserId(todo joinTodo) int {
+	return todo.UserId
+}
+
+func userId(user joinUser) int {
+	return user.Id
+}
+
+var (
+	joinUsers = []joinUser{{1, "ann"}, {2, "bob"}, {3, "cid"}}
+	joinTodos = []joinTodo{{2, "b1"}, {1, "a1"}, {4, "d1"}, {2, "b2"}}
+)
+
+func TestHashJoin(t *testing.T) {
+	got := HashJoin(FromSlice(joinTodos), FromSlice(joinUsers), todoUserId, userId).Collect()
+	want := []Pair[joinTodo, joinUser]{
+		{joinTodo{2, "b1"}, joinUser{2, "bob"}},
+		{joinTodo{1, "a1"}, joinUser{1, "ann"}},
+		{joinTodo{2, "b2"}, joinUser{2, "bob"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("HashJoin() = %v, want %v", got, want)
+	}
+}
+
+func TestHashJoin_Duplicates(t *testing.T) {
+	identity := func(v int) int {
+		return v
+	}
+	got := HashJoin(FromVar(1, 2, 1), FromVar(1, 1, 3), identity, identity).Collect()
+	want := []Pair[int, int]{{1, 1}, {1, 1}, {1, 1}, {1, 1}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("HashJoin() = %v, want %v", got, want)
+	}
+}
+
+func TestLeftJoin(t *testing.T) {
+	got := LeftJoin(FromSlice(joinTodos), FromSlice(joinUsers), todoUserId, userId).Collect()
+	want := []Pair[joinTodo, Optional[joinUser]]{
+		{joinTodo{2, "b1"}, OptionalOf(joinUser{2, "bob"})},
+		{joinTodo{1, "a1"}, OptionalOf(joinUser{1, "ann"})},
+		{joinTodo{4, "d1"}, EmptyOptional[joinUser]()},
+		{joinTodo{2, "b2"}, OptionalOf(joinUser{2, "bob"})},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("LeftJoin() = %v, want %v", got, want)
+	}
+}
+
+func TestFullOuterJoin(t *testing.T) {
+	got := FullOuterJoin(FromSlice(joinTodos), FromSlice(joinUsers), todoUserId, userId).Collect()
+	want := []Pair[Optional[joinTodo], Optional[joinUser]]{
+		{OptionalOf(joinTodo{2, "b1"}), OptionalOf(joinUser{2, "bob"})},
+		{OptionalOf(joinTodo{1, "a1"}), OptionalOf(joinUser{1, "ann"})},
+		{OptionalOf(joinTodo{4, "d1"}), EmptyOptional[joinUser]()},
+		{OptionalOf(joinTodo{2, "b2"}), OptionalOf(joinUser{2, "bob"})},
+		{EmptyOptional[joinTodo](), OptionalOf(joinUser{3, "cid"})},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FullOuterJoin() = %v, want %v", got, want)
+	}
+
+	if got := FullOuterJoin(FromVar[joinTodo](), FromSlice(joinUsers), todoUserId, userId).Count(); got != 3 {
+		t.Errorf("FullOuterJoin() count = %v, want 3", got)
+	}
+}
+
+func TestSortMergeJoin(t *testing.T) {
+	identity := func(v int) int {
+		return v
+	}
+	type testCase[T any] struct {
+		name string
+		s    Stream[T]
+		want []T
+	}
+	tests := []testCase[Pair[int, int]]{
+		{"duplicates", SortMergeJoin(FromVar(1, 2, 2, 4), FromVar(2, 3, 4, 4), identity, identity), []Pair[int, int]{{2, 2}, {2, 2}, {4, 4}, {4, 4}}},
+		{"many to many", SortMergeJoin(FromVar(1, 1), FromVar(1, 1), identity, identity), []Pair[int, int]{{1, 1}, {1, 1}, {1, 1}, {1, 1}}},
+		{"none", SortMergeJoin(FromVar(1, 3), FromVar(2, 4), identity, identity), []Pair[int, int]{}},
+		{"empty left", SortMergeJoin(FromVar[int](), FromVar(1), identity, identity), []Pair[int, int]{}},
+		{"empty right", SortMergeJoin(FromVar(1), FromVar[int](), identity, identity), []Pair[int, int]{}},
+		{"infinite left", SortMergeJoin(Range(0, 1<<62, 1), FromVar(3, 5), identity, identity), []Pair[int, int]{{3, 3}, {5, 5}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.s.Collect(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SortMergeJoin() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	users := SortedBy(FromSlice(joinUsers), userId)
+	todos := SortedBy(FromSlice(joinTodos), todoUserId)
+	got := Map(SortMergeJoin(todos, users, todoUserId, userId), func(p Pair[joinTodo, joinUser]) string {
+		return p.Second.Name + ":" + p.First.Title
+	}).Collect()
+	if want := []string{"ann:a1", "bob:b1", "bob:b2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SortMergeJoin() = %v, want %v", got, want)
+	}
+}
+
+func TestJoin_Err(t *testing.T) {
+	failure := errors.New("failure")
+	identity := func(v int) int {
+		return v
+	}
+	tests := []struct {
+		name string
+		s    Stream[Pair[int, int]]
+	}{
+		{"HashJoin build", HashJoin(FromVar(1), FromSource[int](newFailingSource(failure, 1)), identity, identity)},
+		{"HashJoin probe", HashJoin(FromSource[int](newFailingSource(failure, 1)), FromVar(1), identity, identity)},
+		{"SortMergeJoin left", SortMergeJoin(FromSource[int](newFailingSource(failure, 1)), FromVar(1, 2), identity, identity)},
+		{"SortMergeJoin right", SortMergeJoin(FromVar(1, 2), FromSource[int](newFailingSource(failure, 1)), identity, identity)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.s.CollectErr(); !errors.Is(err, failure) {
+				t.Errorf("%s error = %v, want %v", tt.name, err, failure)
+			}
+		})
+	}
+}
+
+func TestFullOuterJoin_LeftErr(t *testing.T) {
+	identity := func(v int) int {
+		return v
+	}
+	// the unmatched right elements are not emitted, since their matches may be in the unread part of left
+	got, err := FullOuterJoin(FromSource[int](newFailingSource(errBroken, 1)), FromVar(1, 2, 3), identity, identity).CollectErr()
+	want := []Pair[Optional[int], Optional[int]]{{OptionalOf(1), OptionalOf(1)}}
+	if !reflect.DeepEqual(got, want) || err != errBroken {
+		t.Errorf("FullOuterJoin() = %v, %v, want %v, %v", got, err, want, errBroken)
+	}
+}
+
+func TestJoin_Close(t *testing.T) {
+	identity := func(v int) int {
+		return v
+	}
+	tests := []struct {
+		name string
+		join func(left Stream[int], right Stream[int]) int
+	}{
+		{"HashJoin", func(left Stream[int], right Stream[int]) int {
+			return HashJoin(left, right, identity, identity).Take(1).Count()
+		}},
+		{"LeftJoin", func(left Stream[int], right Stream[int]) int {
+			return LeftJoin(left, right, identity, identity).Take(1).Count()
+		}},
+		{"FullOuterJoin", func(left Stream[int], right Stream[int]) int {
+			return FullOuterJoin(left, right, identity, identity).Take(1).Count()
+		}},
+		{"SortMergeJoin", func(left Stream[int], right Stream[int]) int {
+			return SortMergeJoin(left, right, identity, identity).Take(1).Count()
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			left, right := newClosingSource(1, 2, 3), newClosingSource(1, 2)
+			if got := tt.join(FromSource[int](left), FromSource[int](right)); got != 1 {
+				t.Errorf("%s count = %v, want 1", tt.name, got)
+			}
+			if left.Closed() != 1 || right.Closed() != 1 {
+				t.Errorf("closed %d, %d times, want 1, 1", left.Closed(), right.Closed())
+			}
+		})
+	}
+}
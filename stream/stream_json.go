@@ -0,0 +1,91 @@
+package stream
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// FromJSONLines build a Stream of the JSON values of given reader in JSON Lines (NDJSON) format,
+// each line is decoded into T, and the blank lines are skipped.
+// an error of reading or decoding a line ends the stream and is reported by Err.
+func FromJSONLines[T any](r io.Reader, opts ...ReaderOption) Stream[T] {
+	o := newReaderOptions(opts)
+	br := bufio.NewReader(r)
+	line := 0
+	return FromSource[T](newReaderSource(r, o, func() (v T, err error) {
+		for {
+			token, err := readToken(br, '\n', o.maxSize)
+			if err != nil {
+				return v, err
+			}
+			line++
+			token = bytes.TrimSpace(token)
+			if len(token) == 0 {
+				continue
+			}
+			if err := json.Unmarshal(token, &v); err != nil {
+				return v, fmt.Errorf("stream: json line %d: %w", line, err)
+			}
+			return v, nil
+		}
+	}))
+}
+
+// FromJSONArray build a Stream of the elements of a top-level JSON array of given reader.
+// the elements are decoded into T one by one as the stream is pulled, so the array is not loaded at once.
+// an error of reading or decoding an element ends the stream and is reported by Err.
+func FromJSONArray[T any](r io.Reader, opts ...ReaderOption) Stream[T] {
+	o := newReaderOptions(opts)
+	dec := json.NewDecoder(r)
+	started := false
+	return FromSource[T](newReaderSource(r, o, func() (v T, err error) {
+		if !started {
+			started = true
+			token, err := dec.Token()
+			if err == io.EOF {
+				return v, io.EOF
+			}
+			if err != nil {
+				return v, err
+			}
+			if delim, ok := token.(json.Delim); !ok || delim != '[' {
+				return v, fmt.Errorf("stream: json array expected, got %v", token)
+			}
+		}
+		if !dec.More() {
+			// the closing bracket
+			if _, err := dec.Token(); err != nil {
+				return v, err
+			}
+			return v, io.EOF
+		}
+		if err := dec.Decode(&v); err != nil {
+			return v, fmt.Errorf("stream: json array element at offset %d: %w", dec.InputOffset(), err)
+		}
+		return v, nil
+	}))
+}
+
+// ToJSONLines writes the elements of the stream to given writer in JSON Lines (NDJSON) format.
+// it returns the first error of encoding or writing an element, or the error of the stream.
+func ToJSONLines[T any](s Stream[T], w io.Writer) error {
+	up := toBase[T](s)
+	if up == nil {
+		return nil
+	}
+	if onerror := up.getonrecover(); onerror != nil {
+		defer onerror()
+	}
+	defer up.close()
+
+	enc := json.NewEncoder(w)
+	for up.next() {
+		if err := enc.Encode(up.get().(T)); err != nil {
+			return err
+		}
+	}
+	return up.geterr()
+}
@@ -0,0 +1,169 @@
+package stream
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type jsonItem struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestFromJSONLines(t *testing.T) {
+	type testCase struct {
+		name  string
+		input string
+		want  []jsonItem
+	}
+	tests := []testCase{
+		{
+			name:  "empty",
+			input: "",
+			want:  []jsonItem{},
+		},
+		{
+			name:  "lines",
+			input: "{\"id\":1,\"name\":\"a\"}\n{\"id\":2,\"name\":\"b\"}\n",
+			want:  []jsonItem{{1, "a"}, {2, "b"}},
+		},
+		{
+			name:  "blank lines and no trailing newline",
+			input: "\n{\"id\":1}\r\n  \n{\"id\":2}",
+			want:  []jsonItem{{ID: 1}, {ID: 2}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FromJSONLines[jsonItem](strings.NewReader(tt.input)).CollectErr()
+			if !reflect.DeepEqual(got, tt.want) || err != nil {
+				t.Errorf("FromJSONLines() = %v, %v, want %v", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromJSONLines_Error(t *testing.T) {
+	got, err := FromJSONLines[jsonItem](strings.NewReader("{\"id\":1}\n{\"id\":\"x\"}\n{\"id\":3}\n")).CollectErr()
+	if want := []jsonItem{{ID: 1}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("FromJSONLines() = %v, want %v", got, want)
+	}
+	var typeErr *json.UnmarshalTypeError
+	if !errors.As(err, &typeErr) || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("FromJSONLines() err = %v, want an UnmarshalTypeError of line 2", err)
+	}
+}
+
+func TestFromJSONArray(t *testing.T) {
+	type testCase struct {
+		name    string
+		input   string
+		want    []jsonItem
+		wantErr bool
+	}
+	tests := []testCase{
+		{
+			name:  "empty input",
+			input: "",
+			want:  []jsonItem{},
+		},
+		{
+			name:  "empty array",
+			input: " [ ] ",
+			want:  []jsonItem{},
+		},
+		{
+			name:  "array",
+			input: "[{\"id\":1,\"name\":\"a\"},\n {\"id\":2,\"name\":\"b\"}]",
+			want:  []jsonItem{{1, "a"}, {2, "b"}},
+		},
+		{
+			name:    "not an array",
+			input:   "{\"id\":1}",
+			want:    []jsonItem{},
+			wantErr: true,
+		},
+		{
+			name:    "broken element",
+			input:   "[{\"id\":1}, {\"id\":}]",
+			want:    []jsonItem{{ID: 1}},
+			wantErr: true,
+		},
+		{
+			name:    "unterminated",
+			input:   "[{\"id\":1}",
+			want:    []jsonItem{{ID: 1}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FromJSONArray[jsonItem](strings.NewReader(tt.input)).CollectErr()
+			if !reflect.DeepEqual(got, tt.want) || (err != nil) != tt.wantErr {
+				t.Errorf("FromJSONArray() = %v, %v, want %v, error %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+// countingReader counts the bytes read from the reader.
+type countingReader struct {
+	r    io.Reader
+	read int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.read += n
+	return n, err
+}
+
+func TestFromJSONArray_Incremental(t *testing.T) {
+	var input strings.Builder
+	input.WriteString("[")
+	for i := 0; i < 100000; i++ {
+		if i > 0 {
+			input.WriteString(",")
+		}
+		fmt.Fprintf(&input, "{\"id\":%d}", i)
+	}
+	input.WriteString("]")
+
+	r := &countingReader{r: strings.NewReader(input.String())}
+	got := FromJSONArray[jsonItem](r).Take(2).Collect()
+	if want := []jsonItem{{ID: 0}, {ID: 1}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("FromJSONArray() = %v, want %v", got, want)
+	}
+	if r.read >= input.Len()/2 {
+		t.Errorf("FromJSONArray() read %d of %d bytes for 2 elements", r.read, input.Len())
+	}
+}
+
+func TestToJSONLines(t *testing.T) {
+	items := []jsonItem{{1, "a"}, {2, "b"}}
+
+	var buf bytes.Buffer
+	if err := ToJSONLines(FromSlice(items), &buf); err != nil {
+		t.Fatalf("ToJSONLines() err = %v", err)
+	}
+	if want := "{\"id\":1,\"name\":\"a\"}\n{\"id\":2,\"name\":\"b\"}\n"; buf.String() != want {
+		t.Errorf("ToJSONLines() = %q, want %q", buf.String(), want)
+	}
+
+	got, err := FromJSONLines[jsonItem](&buf).CollectErr()
+	if !reflect.DeepEqual(got, items) || err != nil {
+		t.Errorf("FromJSONLines(ToJSONLines()) = %v, %v, want %v", got, err, items)
+	}
+
+	buf.Reset()
+	err = ToJSONLines(FromSource[jsonItem](newFailingSource(errBroken, jsonItem{ID: 1})), &buf)
+	if want := "{\"id\":1,\"name\":\"\"}\n"; buf.String() != want || err != errBroken {
+		t.Errorf("ToJSONLines() = %q, %v, want %q, %v", buf.String(), err, want, errBroken)
+	}
+}
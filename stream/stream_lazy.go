@@ -1,6 +1,7 @@
 package stream
 
 import (
+	"context"
 	"errors"
 	"reflect"
 )
@@ -38,10 +39,36 @@ type Stream[T any] interface {
 	// the function return any typed source.
 	FlatMapConcatAny(f func(T) Source[any]) Stream[any]
 
+	// FlatMapConcurrent returns a stream consisting of the contents of the mapped sources,
+	// which are drained by at most concurrency goroutines, in the order of this stream.
+	FlatMapConcurrent(concurrency int, f func(T) Source[T]) Stream[T]
+	// FlatMapMerge returns a stream consisting of the contents of the mapped sources,
+	// which are drained by at most concurrency goroutines, in the order they are drained.
+	FlatMapMerge(concurrency int, f func(T) Source[T]) Stream[T]
+
+	// ParallelMap returns a stream consisting of the results of applying the given function to the elements of this stream
+	// with at most workers goroutines, the results keep the order of this stream.
+	ParallelMap(workers int, mapf func(T) T) Stream[T]
+	// ParallelMapUnordered returns a stream consisting of the results of applying the given function to the elements of this stream
+	// with at most workers goroutines, the results are emitted as they complete.
+	ParallelMapUnordered(workers int, mapf func(T) T) Stream[T]
+
 	// Take returns a stream consisting of the first n elements of this stream.
 	Take(n int) Stream[T]
 	// Skip returns a stream consisting of the remaining elements of this stream after discarding the first n elements of the stream.
 	Skip(n int) Stream[T]
+	// TakeWhile returns a stream consisting of the longest prefix of this stream whose elements match the predicate.
+	TakeWhile(predicate func(T) bool) Stream[T]
+	// TakeUntil returns a stream consisting of the elements of this stream until an element matches the predicate.
+	TakeUntil(predicate func(T) bool, inclusive bool) Stream[T]
+	// DropWhile returns a stream consisting of the remaining elements of this stream after dropping the longest prefix whose elements match the predicate.
+	DropWhile(predicate func(T) bool) Stream[T]
+	// TakeLast returns a stream consisting of the last n elements of this stream.
+	TakeLast(n int) Stream[T]
+	// SkipLast returns a stream consisting of the elements of this stream except the last n elements.
+	SkipLast(n int) Stream[T]
+	// Slice returns a stream consisting of the elements of this stream from index from to index to exclusive.
+	Slice(from int, to int) Stream[T]
 
 	// Distinct returns a stream consisting of the subsequent distinct elements of this stream.
 	Distinct() Stream[T]
@@ -64,6 +91,25 @@ type Stream[T any] interface {
 	// an associative accumulation function that returns any type.
 	ScanAny(init any, accumf func(acc any, ele T) any) Stream[any]
 
+	// OnComplete returns a stream that calls the callback once when this stream ends, fails or is closed.
+	OnComplete(callback func(err error)) Stream[T]
+	// Close closes the upstream sources of this stream, which are io.Closer.
+	// the terminal operations close the stream, so it is needed only when the stream is pulled by Next and Get.
+	Close() error
+
+	// Sorted returns a stream consisting of the elements of this stream sorted by less.
+	Sorted(less func(a T, b T) bool) Stream[T]
+	// TopK returns a stream consisting of the first k elements of this stream in the order of less.
+	TopK(k int, less func(a T, b T) bool) Stream[T]
+
+	// ChunkAny returns a stream consisting of the tumbling windows of n elements of this stream.
+	// the elements of the stream are []T, use Chunk for Stream[[]T].
+	ChunkAny(n int) Stream[any]
+	// WindowAny returns a stream consisting of the windows of size elements of this stream,
+	// a new window starts every step elements.
+	// the elements of the stream are []T, use Window for Stream[[]T].
+	WindowAny(size int, step int, partial PartialWindow) Stream[any]
+
 	// OnEach returns a stream do nothing but visit each element of this stream.
 	OnEach(visit func(v T)) Stream[T]
 
@@ -71,7 +117,23 @@ type Stream[T any] interface {
 	// the last call of OnRecover is applied when the stream is consumed.
 	OnRecover(onerror RecoverFunc) Stream[T]
 
-	Collector[T]
+	// Catch returns a stream that replaces an element which failed in the upstream with the result of the handler.
+	Catch(handler func(err error) T) Stream[T]
+	// SkipOnError returns a stream that drops the elements which failed in the upstream.
+	SkipOnError() Stream[T]
+	// OnErrorReturn returns a stream that emits the given value and ends when the upstream fails.
+	OnErrorReturn(value T) Stream[T]
+	// OnErrorResume returns a stream that continues with the fallback source when the upstream fails.
+	OnErrorResume(fallback Source[T]) Stream[T]
+
+	// WithContext returns a stream that ends when the given context is done.
+	WithContext(ctx context.Context) Stream[T]
+
+	// Err returns the first error of the upstream sources,
+	// it is nil if the stream ended without an error.
+	Err() error
+
+	Terminal[T]
 }
 
 // Source is source of a stream.
@@ -85,6 +147,24 @@ type Source[T any] interface {
 	Get() T
 }
 
+// ErrSource is a Source which can fail, like sql.Rows or bufio.Scanner.
+// Next returns false when the source fails, and Err returns the error.
+// Err returns nil when the source simply ended.
+type ErrSource[T any] interface {
+	Source[T]
+
+	// Err returns the error, if any, that was encountered during iteration.
+	Err() error
+}
+
+// sourceErr returns the error of the given source if it is an ErrSource.
+func sourceErr[T any](source Source[T]) error {
+	if errsource, ok := source.(interface{ Err() error }); ok {
+		return errsource.Err()
+	}
+	return nil
+}
+
 type Indexed[T any] struct {
 	Index int
 	Value T
@@ -92,21 +172,44 @@ type Indexed[T any] struct {
 
 // terminal operations
 
-// Collector is a terminal operation that collects the stream elements into a container.
-type Collector[T any] interface {
+// Terminal is the terminal operations that consume the stream elements.
+// use CollectWith for a composable Collector.
+type Terminal[T any] interface {
 
 	// ForEach performs an action for each element of this stream.
 	ForEach(visit func(ele T))
 	// ForEachIndex performs an action for each element of this stream.
 	ForEachIndex(visit func(int, T))
+	// ForEachErr performs an action for each element of this stream,
+	// and returns the first error of the upstream sources.
+	ForEachErr(visit func(ele T)) error
+	// ForEachCtx performs an action for each element of this stream until the given context is done,
+	// and returns ctx.Err() or the first error of the upstream sources.
+	ForEachCtx(ctx context.Context, visit func(ele T)) error
 
 	// Collect returns a slice containing the elements of this stream.
 	Collect() (target []T)
 	// CollectTo collects the stream elements into a given slice.
 	CollectTo(target []T) []T
+	// CollectErr returns a slice containing the elements of this stream,
+	// and the first error of the upstream sources.
+	// the elements collected before the error are returned with the error.
+	CollectErr() (target []T, err error)
+	// CollectCtx returns a slice containing the elements of this stream until the given context is done,
+	// and ctx.Err() or the first error of the upstream sources.
+	CollectCtx(ctx context.Context) (target []T, err error)
+	// ToChan returns a channel receiving the elements of this stream, which are sent by a goroutine.
+	ToChan(buffer int) <-chan T
+	// ToChanCtx returns a channel receiving the elements of this stream until the given context is done,
+	// and a channel receiving the error of the stream when the elements channel is closed.
+	ToChanCtx(ctx context.Context, buffer int) (<-chan T, <-chan error)
 
 	// Reduce performs a reduction on the elements of this stream,
+	// the zero value is returned if the stream is empty, see ReduceOpt.
 	Reduce(reducer func(acc T, ele T) T) (result T)
+	// ReduceOpt performs a reduction on the elements of this stream,
+	// and returns an empty Optional if the stream is empty.
+	ReduceOpt(reducer func(acc T, ele T) T) Optional[T]
 	// ReduceAny performs a reduction on the elements of this stream,
 	// an associative accumulation function that returns any type,
 	// and returns the reduced value as any type.
@@ -114,24 +217,41 @@ type Collector[T any] interface {
 
 	// Fold performs a reduction on the elements of this stream,
 	Fold(init T, reducer func(acc T, ele T) T) (result T)
+	// FoldErr performs a reduction on the elements of this stream,
+	// and returns the first error of the upstream sources.
+	FoldErr(init T, reducer func(acc T, ele T) T) (result T, err error)
 	// FoldAny performs a reduction on the elements of this stream,
 	// an associative accumulation function that returns any type,
 	// and returns the reduced value as any type.
 	FoldAny(init any, reducer func(acc any, ele T) any) (result any)
 
-	// Find returns the first element of this stream matching the given predicate
+	// Find returns the first element of this stream matching the given predicate,
+	// or ErrNotFound if no such element exists.
 	Find(predicate func(T) bool) (found T, err error)
+	// FindFirst returns the first element of this stream matching the given predicate,
+	// or an empty Optional if no such element exists.
+	FindFirst(predicate func(T) bool) Optional[T]
 	// FindOr returns the first element of this stream matching the given predicate, or default value if no such element exists.
 	FindOr(predicate func(ele T) bool, defvalue T) T
 	// FindIndex returns the index of the first element of this down stream, not source, matching the given predicate,
 	FindIndex(predicate func(T) bool) int
-	// FindLast returns the last element of this stream matching the given predicate.
+	// FindLast returns the last element of this stream matching the given predicate,
+	// or ErrNotFound if no such element exists.
 	FindLast(predicate func(T) bool) (found T, err error)
 	// FindLastOr returns the last element of this stream matching the given predicate, or default value if no such element exists.
 	FindLastOr(predicate func(T) bool, defvalue T) (found T)
 	// FindLastIndex returns the index of the last element of this stream matching the given predicate.
 	FindLastIndex(predicate func(T) bool) (found int)
 
+	// First returns the first element of this stream, or an empty Optional if the stream is empty.
+	First() Optional[T]
+	// Last returns the last element of this stream, or an empty Optional if the stream is empty.
+	Last() Optional[T]
+	// Min returns the first smallest element of this stream by less, or an empty Optional if the stream is empty.
+	Min(less func(a T, b T) bool) Optional[T]
+	// Max returns the first largest element of this stream by less, or an empty Optional if the stream is empty.
+	Max(less func(a T, b T) bool) Optional[T]
+
 	// Count returns the count of elements of this stream.
 	// it does not consume the stream, specifically, it does not call Get().
 	Count() int
@@ -149,6 +269,8 @@ type baseStream[T any] struct {
 	next         func() bool
 	get          func() any
 	getonrecover func() RecoverFunc
+	geterr       func() error
+	close        func() error // releases the upstream sources, it is called by the terminal operations
 }
 
 //
@@ -172,6 +294,13 @@ func (s *baseStream[T]) Get() (result T) {
 	return s.get().(T)
 }
 
+func (s *baseStream[T]) Err() error {
+	if s == nil {
+		return nil
+	}
+	return s.geterr()
+}
+
 //
 // stream operations
 //
@@ -198,6 +327,12 @@ func (s *baseStream[T]) Filter(filter func(T) bool) Stream[T] {
 	filterstream.getonrecover = func() RecoverFunc {
 		return s.getonrecover()
 	}
+	filterstream.geterr = func() error {
+		return s.geterr()
+	}
+	filterstream.close = func() error {
+		return s.close()
+	}
 	return filterstream
 }
 
@@ -222,6 +357,12 @@ func (s *baseStream[T]) Map(mapf func(T) T) Stream[T] {
 	mapstream.getonrecover = func() RecoverFunc {
 		return s.getonrecover()
 	}
+	mapstream.geterr = func() error {
+		return s.geterr()
+	}
+	mapstream.close = func() error {
+		return s.close()
+	}
 	return mapstream
 }
 
@@ -247,6 +388,12 @@ func (s *baseStream[T]) MapAny(mapf func(T) any) Stream[any] {
 	mapstream.getonrecover = func() RecoverFunc {
 		return s.getonrecover()
 	}
+	mapstream.geterr = func() error {
+		return s.geterr()
+	}
+	mapstream.close = func() error {
+		return s.close()
+	}
 	return mapstream
 }
 
@@ -271,6 +418,12 @@ func (s *baseStream[T]) MapIndex(mapf func(int, T) T) Stream[T] {
 	mapstream.getonrecover = func() RecoverFunc {
 		return s.getonrecover()
 	}
+	mapstream.geterr = func() error {
+		return s.geterr()
+	}
+	mapstream.close = func() error {
+		return s.close()
+	}
 	return mapstream
 }
 
@@ -295,12 +448,29 @@ func (s *baseStream[T]) MapIndexAny(mapf func(int, T) any) Stream[any] {
 	mapstream.getonrecover = func() RecoverFunc {
 		return s.getonrecover()
 	}
+	mapstream.geterr = func() error {
+		return s.geterr()
+	}
+	mapstream.close = func() error {
+		return s.close()
+	}
 	return mapstream
 }
 
 type fmapStream[T any] struct {
 	baseStream[T]
 	source Source[T]
+	err    error // error of the inner source
+}
+
+// closeSource closes the current inner source when it ends or the stream is closed.
+func (s *fmapStream[T]) closeSource() error {
+	if s.source == nil {
+		return nil
+	}
+	source := s.source
+	s.source = nil
+	return closeSource(source)
 }
 
 // FlatMapConcat returns a stream consisting of the results of
@@ -315,9 +485,18 @@ func (s *baseStream[T]) FlatMapConcat(fmap func(ele T) Source[T]) Stream[T] {
 	fmapstream.idx = -1
 	fmapstream.next = func() bool {
 	loop:
-		if fmapstream.source != nil && fmapstream.source.Next() {
-			fmapstream.idx++
-			return true
+		if fmapstream.source != nil {
+			if fmapstream.source.Next() {
+				fmapstream.idx++
+				return true
+			}
+			fmapstream.err = sourceErr(fmapstream.source)
+			if err := fmapstream.closeSource(); fmapstream.err == nil {
+				fmapstream.err = err
+			}
+			if fmapstream.err != nil {
+				return false
+			}
 		}
 		for s.next() {
 			fmapstream.source = fmap(s.get().(T))
@@ -332,6 +511,19 @@ func (s *baseStream[T]) FlatMapConcat(fmap func(ele T) Source[T]) Stream[T] {
 	fmapstream.getonrecover = func() RecoverFunc {
 		return s.getonrecover()
 	}
+	fmapstream.geterr = func() error {
+		if fmapstream.err != nil {
+			return fmapstream.err
+		}
+		return s.geterr()
+	}
+	fmapstream.close = func() error {
+		err := fmapstream.closeSource()
+		if uperr := s.close(); err == nil {
+			err = uperr
+		}
+		return err
+	}
 	return fmapstream
 }
 
@@ -344,9 +536,18 @@ func (s *baseStream[T]) FlatMapConcatAny(fmap func(ele T) Source[any]) Stream[an
 	fmapstream.idx = -1
 	fmapstream.next = func() bool {
 	loop:
-		if fmapstream.source != nil && fmapstream.source.Next() {
-			fmapstream.idx++
-			return true
+		if fmapstream.source != nil {
+			if fmapstream.source.Next() {
+				fmapstream.idx++
+				return true
+			}
+			fmapstream.err = sourceErr(fmapstream.source)
+			if err := fmapstream.closeSource(); fmapstream.err == nil {
+				fmapstream.err = err
+			}
+			if fmapstream.err != nil {
+				return false
+			}
 		}
 		for s.next() {
 			fmapstream.source = fmap(s.get().(T))
@@ -361,6 +562,19 @@ func (s *baseStream[T]) FlatMapConcatAny(fmap func(ele T) Source[any]) Stream[an
 	fmapstream.getonrecover = func() RecoverFunc {
 		return s.getonrecover()
 	}
+	fmapstream.geterr = func() error {
+		if fmapstream.err != nil {
+			return fmapstream.err
+		}
+		return s.geterr()
+	}
+	fmapstream.close = func() error {
+		err := fmapstream.closeSource()
+		if uperr := s.close(); err == nil {
+			err = uperr
+		}
+		return err
+	}
 	return fmapstream
 }
 
@@ -385,6 +599,12 @@ func (s *baseStream[T]) Take(n int) Stream[T] {
 	takestream.getonrecover = func() RecoverFunc {
 		return s.getonrecover()
 	}
+	takestream.geterr = func() error {
+		return s.geterr()
+	}
+	takestream.close = func() error {
+		return s.close()
+	}
 	return takestream
 }
 
@@ -411,6 +631,12 @@ func (s *baseStream[T]) Skip(n int) Stream[T] {
 	skipstream.getonrecover = func() RecoverFunc {
 		return s.getonrecover()
 	}
+	skipstream.geterr = func() error {
+		return s.geterr()
+	}
+	skipstream.close = func() error {
+		return s.close()
+	}
 	return skipstream
 }
 
@@ -460,6 +686,12 @@ func (s *baseStream[T]) DistinctBy(cmp func(old, new T) bool) Stream[T] {
 	distinctstream.getonrecover = func() RecoverFunc {
 		return s.getonrecover()
 	}
+	distinctstream.geterr = func() error {
+		return s.geterr()
+	}
+	distinctstream.close = func() error {
+		return s.close()
+	}
 	return distinctstream
 }
 
@@ -484,6 +716,19 @@ func (s *baseStream[T]) ZipWith(other Source[T], zipf func(T, T) T) Stream[T] {
 	zipstream.getonrecover = func() RecoverFunc {
 		return s.getonrecover()
 	}
+	zipstream.geterr = func() error {
+		if err := s.geterr(); err != nil {
+			return err
+		}
+		return sourceErr(other)
+	}
+	zipstream.close = func() error {
+		err := s.close()
+		if othererr := closeSource(other); err == nil {
+			err = othererr
+		}
+		return err
+	}
 	return zipstream
 }
 
@@ -508,6 +753,19 @@ func (s *baseStream[T]) ZipWithAny(other Source[any], zipf func(T, any) any) Str
 	zipstream.getonrecover = func() RecoverFunc {
 		return s.getonrecover()
 	}
+	zipstream.geterr = func() error {
+		if err := s.geterr(); err != nil {
+			return err
+		}
+		return sourceErr(other)
+	}
+	zipstream.close = func() error {
+		err := s.close()
+		if othererr := closeSource(other); err == nil {
+			err = othererr
+		}
+		return err
+	}
 	return zipstream
 }
 
@@ -542,6 +800,12 @@ func (s *baseStream[T]) ZipWithPrev(zipf func(prev T, ele T) T) Stream[T] {
 	zipstream.getonrecover = func() RecoverFunc {
 		return s.getonrecover()
 	}
+	zipstream.geterr = func() error {
+		return s.geterr()
+	}
+	zipstream.close = func() error {
+		return s.close()
+	}
 	return zipstream
 }
 
@@ -575,6 +839,12 @@ func (s *baseStream[T]) Scan(init T, accumf func(acc T, ele T) T) Stream[T] {
 	scanstream.getonrecover = func() RecoverFunc {
 		return s.getonrecover()
 	}
+	scanstream.geterr = func() error {
+		return s.geterr()
+	}
+	scanstream.close = func() error {
+		return s.close()
+	}
 	return scanstream
 }
 
@@ -600,6 +870,12 @@ func (s *baseStream[T]) ScanAny(init any, accumf func(acc any, ele T) any) Strea
 	scanstream.getonrecover = func() RecoverFunc {
 		return s.getonrecover()
 	}
+	scanstream.geterr = func() error {
+		return s.geterr()
+	}
+	scanstream.close = func() error {
+		return s.close()
+	}
 	return scanstream
 }
 
@@ -626,6 +902,12 @@ func (s *baseStream[T]) OnEach(visit func(v T)) Stream[T] {
 	eachstream.getonrecover = func() RecoverFunc {
 		return s.getonrecover()
 	}
+	eachstream.geterr = func() error {
+		return s.geterr()
+	}
+	eachstream.close = func() error {
+		return s.close()
+	}
 
 	return eachstream
 }
@@ -666,6 +948,12 @@ func (s *baseStream[T]) OnRecover(onrecover RecoverFunc) Stream[T] {
 		}
 		return s.getonrecover()
 	}
+	errstream.geterr = func() error {
+		return s.geterr()
+	}
+	errstream.close = func() error {
+		return s.close()
+	}
 
 	return errstream
 }
@@ -681,10 +969,28 @@ func (s *baseStream[T]) ForEach(visit func(T)) {
 	if onerror := s.getonrecover(); onerror != nil {
 		defer onerror()
 	}
+	defer s.close()
+
+	for s.next() {
+		visit(s.get().(T))
+	}
+}
+
+// ForEachErr performs an action for each element of this stream,
+// and returns the first error of the upstream sources.
+func (s *baseStream[T]) ForEachErr(visit func(T)) error {
+	if s == nil {
+		return nil
+	}
+	if onerror := s.getonrecover(); onerror != nil {
+		defer onerror()
+	}
+	defer s.close()
 
 	for s.next() {
 		visit(s.get().(T))
 	}
+	return s.geterr()
 }
 
 // ForEachIndex performs an action for each element of this stream.
@@ -695,6 +1001,7 @@ func (s *baseStream[T]) ForEachIndex(visit func(int, T)) {
 	if onerror := s.getonrecover(); onerror != nil {
 		defer onerror()
 	}
+	defer s.close()
 
 	idx := -1
 	for s.next() {
@@ -711,6 +1018,7 @@ func (s *baseStream[T]) Collect() (target []T) {
 	if onerror := s.getonrecover(); onerror != nil {
 		defer onerror()
 	}
+	defer s.close()
 
 	target = []T{}
 	for s.next() {
@@ -720,6 +1028,26 @@ func (s *baseStream[T]) Collect() (target []T) {
 	return
 }
 
+// CollectErr returns a slice containing the elements of this stream,
+// and the first error of the upstream sources.
+// the elements collected before the error are returned with the error.
+func (s *baseStream[T]) CollectErr() (target []T, err error) {
+	if s == nil {
+		return []T{}, nil
+	}
+	if onerror := s.getonrecover(); onerror != nil {
+		defer onerror()
+	}
+	defer s.close()
+
+	target = []T{}
+	for s.next() {
+		v := s.get()
+		target = append(target, v.(T))
+	}
+	return target, s.geterr()
+}
+
 // CollectTo collects the stream elements into a given slice.
 func (s *baseStream[T]) CollectTo(target []T) []T {
 	if s == nil {
@@ -728,6 +1056,7 @@ func (s *baseStream[T]) CollectTo(target []T) []T {
 	if onerror := s.getonrecover(); onerror != nil {
 		defer onerror()
 	}
+	defer s.close()
 
 	// nil target
 	if target == nil {
@@ -745,7 +1074,7 @@ func (s *baseStream[T]) CollectTo(target []T) []T {
 // using the provided identity value and an associative accumulation function,
 // and returns the reduced value.
 // [a, b, c, d] => f(f(f(a, b), c), d)
-// if the stream is empty returns nil
+// if the stream is empty returns the zero value, use ReduceOpt to tell it from the reduced value.
 func (s *baseStream[T]) Reduce(reducer func(acc T, ele T) T) (result T) {
 	if s == nil {
 		return
@@ -753,6 +1082,7 @@ func (s *baseStream[T]) Reduce(reducer func(acc T, ele T) T) (result T) {
 	if onerror := s.getonrecover(); onerror != nil {
 		defer onerror()
 	}
+	defer s.close()
 
 	if s.next() {
 		result = s.get().(T)
@@ -778,6 +1108,7 @@ func (s *baseStream[T]) ReduceAny(reducer func(acc any, ele T) any) (result any)
 	if onerror := s.getonrecover(); onerror != nil {
 		defer onerror()
 	}
+	defer s.close()
 
 	if s.next() {
 		result = s.get()
@@ -801,6 +1132,7 @@ func (s *baseStream[T]) Fold(init T, reducer func(acc T, ele T) T) (result T) {
 	if onerror := s.getonrecover(); onerror != nil {
 		defer onerror()
 	}
+	defer s.close()
 
 	result = init
 	for s.next() {
@@ -810,6 +1142,25 @@ func (s *baseStream[T]) Fold(init T, reducer func(acc T, ele T) T) (result T) {
 	return result
 }
 
+// FoldErr performs a reduction on the elements of this stream, using the provided identity value
+// and an associative accumulation function, and returns the reduced value and the first error of the upstream sources.
+func (s *baseStream[T]) FoldErr(init T, reducer func(acc T, ele T) T) (result T, err error) {
+	if s == nil {
+		return init, nil
+	}
+	if onerror := s.getonrecover(); onerror != nil {
+		defer onerror()
+	}
+	defer s.close()
+
+	result = init
+	for s.next() {
+		v := s.get()
+		result = reducer(result, v.(T))
+	}
+	return result, s.geterr()
+}
+
 func (s *baseStream[T]) FoldAny(init any, reducer func(acc any, ele T) any) (result any) {
 	if s == nil {
 		return init
@@ -817,6 +1168,7 @@ func (s *baseStream[T]) FoldAny(init any, reducer func(acc any, ele T) any) (res
 	if onerror := s.getonrecover(); onerror != nil {
 		defer onerror()
 	}
+	defer s.close()
 
 	result = init
 	for s.next() {
@@ -826,7 +1178,8 @@ func (s *baseStream[T]) FoldAny(init any, reducer func(acc any, ele T) any) (res
 	return result
 }
 
-// Find returns the first element of this stream matching the given predicate
+// Find returns the first element of this stream matching the given predicate,
+// or ErrNotFound if no such element exists, the error of the upstream sources is returned instead if any.
 func (s *baseStream[T]) Find(predicate func(T) bool) (found T, err error) {
 	if s == nil {
 		err = errors.New("upstream is nil")
@@ -835,6 +1188,7 @@ func (s *baseStream[T]) Find(predicate func(T) bool) (found T, err error) {
 	if onerror := s.getonrecover(); onerror != nil {
 		defer onerror()
 	}
+	defer s.close()
 
 	for s.next() {
 		v := s.get().(T)
@@ -842,7 +1196,9 @@ func (s *baseStream[T]) Find(predicate func(T) bool) (found T, err error) {
 			return v, nil
 		}
 	}
-	err = nil
+	if err = s.geterr(); err == nil {
+		err = ErrNotFound
+	}
 	return
 }
 
@@ -854,6 +1210,7 @@ func (s *baseStream[T]) FindOr(predicate func(ele T) bool, defvalue T) T {
 	if onerror := s.getonrecover(); onerror != nil {
 		defer onerror()
 	}
+	defer s.close()
 
 	for s.next() {
 		v := s.get().(T)
@@ -873,6 +1230,7 @@ func (s *baseStream[T]) FindIndex(predicate func(T) bool) int {
 	if onerror := s.getonrecover(); onerror != nil {
 		defer onerror()
 	}
+	defer s.close()
 
 	idx := -1
 	for s.next() {
@@ -885,7 +1243,8 @@ func (s *baseStream[T]) FindIndex(predicate func(T) bool) int {
 	return -1
 }
 
-// FindLast returns the last element of this stream matching the given predicate, otherwise -1.
+// FindLast returns the last element of this stream matching the given predicate,
+// or ErrNotFound if no such element exists, the error of the upstream sources is returned instead if any.
 func (s *baseStream[T]) FindLast(predicate func(T) bool) (found T, err error) {
 	if s == nil {
 		err = errors.New("upstream is nil")
@@ -894,14 +1253,20 @@ func (s *baseStream[T]) FindLast(predicate func(T) bool) (found T, err error) {
 	if onerror := s.getonrecover(); onerror != nil {
 		defer onerror()
 	}
+	defer s.close()
 
+	matched := false
 	for s.next() {
 		v := s.get().(T)
 		if predicate(v) {
 			found = v
+			matched = true
 		}
 	}
-	return found, nil
+	if err = s.geterr(); err == nil && !matched {
+		err = ErrNotFound
+	}
+	return found, err
 }
 
 // FindLastOr returns the last element of this stream matching the given predicate,
@@ -913,6 +1278,7 @@ func (s *baseStream[T]) FindLastOr(predicate func(T) bool, defvalue T) (found T)
 	if onerror := s.getonrecover(); onerror != nil {
 		defer onerror()
 	}
+	defer s.close()
 
 	found = defvalue
 	for s.next() {
@@ -932,6 +1298,7 @@ func (s *baseStream[T]) FindLastIndex(predicate func(T) bool) (found int) {
 	if onerror := s.getonrecover(); onerror != nil {
 		defer onerror()
 	}
+	defer s.close()
 
 	idx := -1
 	found = idx
@@ -954,6 +1321,7 @@ func (s *baseStream[T]) Count() (count int) {
 	if onerror := s.getonrecover(); onerror != nil {
 		defer onerror()
 	}
+	defer s.close()
 
 	for s.next() {
 		count++
@@ -968,6 +1336,7 @@ func (s *baseStream[T]) All(predicate func(T) bool) bool {
 	if onerror := s.getonrecover(); onerror != nil {
 		defer onerror()
 	}
+	defer s.close()
 
 	// for empty
 	result := false
@@ -987,6 +1356,7 @@ func (s *baseStream[T]) Any(predicate func(T) bool) bool {
 	if onerror := s.getonrecover(); onerror != nil {
 		defer onerror()
 	}
+	defer s.close()
 
 	// for empty
 	result := false
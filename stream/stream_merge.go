@@ -0,0 +1,386 @@
+package stream
+
+import (
+	"container/heap"
+	"sync"
+)
+
+//
+// multi-source combinators
+//
+// the streams combine several upstreams of the same element type,
+// they report the first error of the upstreams and close all of them.
+// an upstream which ends without an error is closed as soon as it ends.
+//
+
+// upstreams are the upstreams of a stream combining several streams.
+type upstreams[T any] struct {
+	ups    []*baseStream[T]
+	closed []bool
+}
+
+// newUpstreams returns the upstreams of the given streams, nil streams are dropped.
+func newUpstreams[T any](streams []Stream[T]) *upstreams[T] {
+	u := &upstreams[T]{}
+	for _, s := range streams {
+		if up := toBase[T](s); up != nil {
+			u.ups = append(u.ups, up)
+		}
+	}
+	u.closed = make([]bool, len(u.ups))
+	return u
+}
+
+// onrecover returns the first RecoverFunc of the upstreams.
+func (u *upstreams[T]) onrecover() RecoverFunc {
+	for _, up := range u.ups {
+		if onerror := up.getonrecover(); onerror != nil {
+			return onerror
+		}
+	}
+	return nil
+}
+
+// err returns the first error of the upstreams.
+func (u *upstreams[T]) err() error {
+	for _, up := range u.ups {
+		if err := up.geterr(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// closeAt closes the i-th upstream if it is not closed yet.
+func (u *upstreams[T]) closeAt(i int) error {
+	if u.closed[i] {
+		return nil
+	}
+	u.closed[i] = true
+	return u.ups[i].close()
+}
+
+// closeAll closes the upstreams which are not closed yet and returns the first error.
+func (u *upstreams[T]) closeAll() (err error) {
+	for i := range u.ups {
+		if closeerr := u.closeAt(i); err == nil {
+			err = closeerr
+		}
+	}
+	return err
+}
+
+// newMultiStream returns a stream on the upstreams, its next and get are set by the caller.
+func newMultiStream[T any](u *upstreams[T]) *baseStream[T] {
+	stream := new(baseStream[T])
+	stream.idx = -1
+	stream.getonrecover = func() RecoverFunc {
+		return u.onrecover()
+	}
+	stream.geterr = func() error {
+		return u.err()
+	}
+	stream.close = func() error {
+		return u.closeAll()
+	}
+	return stream
+}
+
+// Concat returns a stream consisting of the elements of the streams one after another.
+// the stream ends at the first upstream which fails.
+//
+//	with [1, 2], [3] and [4, 5] produces [1, 2, 3, 4, 5]
+func Concat[T any](streams ...Stream[T]) Stream[T] {
+	u := newUpstreams(streams)
+	cur := 0
+	concatstream := newMultiStream(u)
+	concatstream.next = func() bool {
+		for cur < len(u.ups) {
+			up := u.ups[cur]
+			if up.next() {
+				concatstream.idx++
+				return true
+			}
+			if up.geterr() != nil {
+				return false
+			}
+			u.closeAt(cur)
+			cur++
+		}
+		return false
+	}
+	concatstream.get = func() any {
+		return u.ups[cur].get()
+	}
+	return concatstream
+}
+
+// interleave takes an element from each of the active upstreams in turn.
+// an upstream which ends drops out if skip is true, otherwise it ends the stream.
+func interleave[T any](streams []Stream[T], skip bool) Stream[T] {
+	u := newUpstreams(streams)
+	active := make([]int, len(u.ups))
+	for i := range active {
+		active[i] = i
+	}
+	turn := 0
+	cur := -1
+	done := false
+	interleavestream := newMultiStream(u)
+	interleavestream.next = func() bool {
+		for !done && len(active) > 0 {
+			i := active[turn]
+			if u.ups[i].next() {
+				interleavestream.idx++
+				cur = i
+				turn = (turn + 1) % len(active)
+				return true
+			}
+			if !skip || u.ups[i].geterr() != nil {
+				done = true
+				break
+			}
+			u.closeAt(i)
+			active = append(active[:turn], active[turn+1:]...)
+			if turn == len(active) {
+				turn = 0
+			}
+		}
+		return false
+	}
+	interleavestream.get = func() any {
+		return u.ups[cur].get()
+	}
+	return interleavestream
+}
+
+// Interleave returns a stream consisting of an element of each stream in turn,
+// which is a strict round-robin and ends when any of the streams ends.
+//
+//	with [1, 2, 3], [4, 5] and [6, 7] produces [1, 4, 6, 2, 5, 7, 3]
+func Interleave[T any](streams ...Stream[T]) Stream[T] {
+	return interleave(streams, false)
+}
+
+// RoundRobin returns a stream consisting of an element of each stream in turn,
+// the streams which end are skipped and the stream ends when all of them end.
+//
+//	with [1, 2, 3], [4] and [5, 6] produces [1, 4, 5, 2, 6, 3]
+func RoundRobin[T any](streams ...Stream[T]) Stream[T] {
+	return interleave(streams, true)
+}
+
+// MergeSorted returns a stream consisting of the elements of the streams merged in the order of less,
+// each of which must be sorted by less. the earlier stream wins a tie, so the merge is stable.
+// a heap of the heads of the streams is used, so only one element of each stream is kept in memory.
+//
+//	with [1, 4], [2, 3] and [0, 5] produces [0, 1, 2, 3, 4, 5]
+func MergeSorted[T any](less func(a T, b T) bool, streams ...Stream[T]) Stream[T] {
+	u := newUpstreams(streams)
+	h := &mergeHeap[T]{less: less}
+	var cur T
+	loaded := false
+	done := false
+
+	// advance pushes the next element of the i-th upstream, it returns false if the upstream failed.
+	advance := func(i int) bool {
+		up := u.ups[i]
+		if up.next() {
+			heap.Push(h, mergeItem[T]{value: up.get().(T), run: i})
+			return true
+		}
+		if up.geterr() != nil {
+			return false
+		}
+		u.closeAt(i)
+		return true
+	}
+
+	mergestream := newMultiStream(u)
+	mergestream.next = func() bool {
+		if done {
+			return false
+		}
+		if !loaded {
+			loaded = true
+			for i := range u.ups {
+				if !advance(i) {
+					done = true
+					return false
+				}
+			}
+		}
+		if h.Len() == 0 {
+			done = true
+			return false
+		}
+		item := heap.Pop(h).(mergeItem[T])
+		if !advance(item.run) {
+			done = true
+			return false
+		}
+		mergestream.idx++
+		cur = item.value
+		return true
+	}
+	mergestream.get = func() any {
+		return cur
+	}
+	return mergestream
+}
+
+// mergeEvent is an element or the end of an upstream of a merger.
+type mergeEvent[T any] struct {
+	source int // index of the upstream
+	value  T
+	ended  bool  // the upstream ended
+	err    error // recovered from a panic of the upstream
+}
+
+// merger pulls the upstreams by their own goroutines and delivers their elements in the order they are produced.
+// each goroutine closes its upstream when the upstream ends or the merger is stopped,
+// so an upstream is never pulled and closed concurrently.
+type merger[T any] struct {
+	u        *upstreams[T]
+	events   chan mergeEvent[T]
+	errs     []error // written by the goroutine of the upstream before its end is delivered
+	ended    []bool
+	running  int
+	done     bool // stopped by the consumer
+	stopped  chan struct{}
+	stopOnce sync.Once
+}
+
+func newMerger[T any](u *upstreams[T]) *merger[T] {
+	return &merger[T]{
+		u:       u,
+		errs:    make([]error, len(u.ups)),
+		ended:   make([]bool, len(u.ups)),
+		stopped: make(chan struct{}),
+	}
+}
+
+func (m *merger[T]) send(event mergeEvent[T]) bool {
+	select {
+	case m.events <- event:
+		return true
+	case <-m.stopped:
+		return false
+	}
+}
+
+func (m *merger[T]) start() {
+	m.events = make(chan mergeEvent[T])
+	m.running = len(m.u.ups)
+	for i, up := range m.u.ups {
+		go func(i int, up *baseStream[T]) {
+			defer up.close()
+			func() {
+				defer func() {
+					if r := recover(); r != nil {
+						m.send(mergeEvent[T]{source: i, err: recoverErr(r)})
+					}
+				}()
+				for up.next() {
+					if !m.send(mergeEvent[T]{source: i, value: up.get().(T)}) {
+						return
+					}
+				}
+				m.errs[i] = up.geterr()
+			}()
+			m.send(mergeEvent[T]{source: i, ended: true})
+		}(i, up)
+	}
+}
+
+// next returns the next event, it returns false when all the upstreams ended.
+// a panic while pulling an upstream is raised again on the consuming goroutine.
+func (m *merger[T]) next() (event mergeEvent[T], ok bool) {
+	if m.done {
+		return event, false
+	}
+	if m.events == nil {
+		m.start()
+	}
+	if m.running == 0 {
+		return event, false
+	}
+	event = <-m.events
+	if event.err != nil {
+		panic(event.err)
+	}
+	if event.ended {
+		m.ended[event.source] = true
+		m.running--
+	}
+	return event, true
+}
+
+// err returns the first error of the upstreams which ended.
+// errs[i] is read only after the end of the i-th upstream is delivered,
+// since the goroutine of an upstream which has not ended may still write it.
+func (m *merger[T]) err() error {
+	for i, ended := range m.ended {
+		if ended && m.errs[i] != nil {
+			return m.errs[i]
+		}
+	}
+	return nil
+}
+
+// stop stops the goroutines, the upstreams are closed here if the merger has not started.
+func (m *merger[T]) stop() error {
+	m.done = true
+	if m.events == nil {
+		return m.u.closeAll()
+	}
+	// the upstreams are closed by their goroutines
+	m.stopOnce.Do(func() {
+		close(m.stopped)
+	})
+	return nil
+}
+
+// Merge returns a stream consisting of the elements of the streams in the order they are produced.
+// each stream is pulled by its own goroutine, which closes the stream when it ends or the merged stream is closed.
+// the errors of the streams are reported by Err after all of them end,
+// and a panic while pulling a stream is raised again on the consuming goroutine.
+func Merge[T any](streams ...Stream[T]) Stream[T] {
+	u := newUpstreams(streams)
+	m := newMerger(u)
+	var cur T
+	done := false
+
+	mergestream := new(baseStream[T])
+	mergestream.idx = -1
+	mergestream.next = func() bool {
+		for !done {
+			event, ok := m.next()
+			if !ok {
+				done = true
+				break
+			}
+			if event.ended {
+				continue
+			}
+			mergestream.idx++
+			cur = event.value
+			return true
+		}
+		return false
+	}
+	mergestream.get = func() any {
+		return cur
+	}
+	mergestream.getonrecover = func() RecoverFunc {
+		return u.onrecover()
+	}
+	mergestream.geterr = func() error {
+		return m.err()
+	}
+	mergestream.close = func() error {
+		done = true
+		return m.stop()
+	}
+	return mergestream
+}
@@ -0,0 +1,231 @@
+package stream
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestConcat(t *testing.T) {
+	type testCase[T any] struct {
+		name string
+		s    Stream[T]
+		want []T
+	}
+	tests := []testCase[int]{
+		{"none", Concat[int](), []int{}},
+		{"one", Concat(FromVar(1, 2)), []int{1, 2}},
+		{"many", Concat(FromVar(1, 2), FromVar(3), FromVar(4, 5)), []int{1, 2, 3, 4, 5}},
+		{"empty", Concat(FromVar[int](), FromVar(1), FromVar[int]()), []int{1}},
+		{"nil", Concat(nil, FromVar(1), nil), []int{1}},
+		{"infinite", Concat(FromVar(1), Repeat(2, -1)).Take(3), []int{1, 2, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.s.Collect(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Concat() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInterleave(t *testing.T) {
+	type testCase[T any] struct {
+		name string
+		s    Stream[T]
+		want []T
+	}
+	tests := []testCase[int]{
+		{"none", Interleave[int](), []int{}},
+		{"even", Interleave(FromVar(1, 2), FromVar(3, 4)), []int{1, 3, 2, 4}},
+		{"strict", Interleave(FromVar(1, 2, 3), FromVar(4, 5), FromVar(6, 7)), []int{1, 4, 6, 2, 5, 7, 3}},
+		{"empty", Interleave(FromVar(1, 2), FromVar[int]()), []int{1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.s.Collect(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Interleave() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoundRobin(t *testing.T) {
+	type testCase[T any] struct {
+		name string
+		s    Stream[T]
+		want []T
+	}
+	tests := []testCase[int]{
+		{"none", RoundRobin[int](), []int{}},
+		{"skip", RoundRobin(FromVar(1, 2, 3), FromVar(4), FromVar(5, 6)), []int{1, 4, 5, 2, 6, 3}},
+		{"last", RoundRobin(FromVar(1), FromVar(2, 3, 4)), []int{1, 2, 3, 4}},
+		{"empty", RoundRobin(FromVar[int](), FromVar(1, 2)), []int{1, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.s.Collect(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RoundRobin() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeSorted(t *testing.T) {
+	type testCase[T any] struct {
+		name string
+		s    Stream[T]
+		want []T
+	}
+	tests := []testCase[int]{
+		{"none", MergeSorted(lessInt), []int{}},
+		{"merge", MergeSorted(lessInt, FromVar(1, 4), FromVar(2, 3), FromVar(0, 5)), []int{0, 1, 2, 3, 4, 5}},
+		{"empty", MergeSorted(lessInt, FromVar[int](), FromVar(1, 2)), []int{1, 2}},
+		{"duplicates", MergeSorted(lessInt, FromVar(1, 1, 3), FromVar(1, 2)), []int{1, 1, 1, 2, 3}},
+		{"infinite", MergeSorted(lessInt, Range(0, 1000, 2), Iterate(1, func(v int) int { return v + 2 })).Take(5), []int{0, 1, 2, 3, 4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.s.Collect(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MergeSorted() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeSorted_Stable(t *testing.T) {
+	less := func(a, b sortItem) bool {
+		return a.Key < b.Key
+	}
+	first := FromVar(sortItem{1, 0}, sortItem{2, 0})
+	second := FromVar(sortItem{1, 1}, sortItem{2, 1})
+	got := MergeSorted(less, first, second).Collect()
+	want := []sortItem{{1, 0}, {1, 1}, {2, 0}, {2, 1}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MergeSorted() = %v, want %v", got, want)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	defer checkGoroutines(t)()
+
+	got := Merge(FromVar(1, 2, 3), FromChan(FromVar(4, 5).ToChan(0)), FromVar[int](), Range(6, 10, 1)).Collect()
+	sort.Ints(got)
+	want := []int{1, 2, 3, 4, 5, 6, 7, 8, 9}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Merge() = %v, want %v", got, want)
+	}
+
+	if got := Merge[int]().Collect(); len(got) != 0 {
+		t.Errorf("Merge() = %v, want empty", got)
+	}
+}
+
+func TestMerge_Close(t *testing.T) {
+	defer checkGoroutines(t)()
+
+	sources := []*closingSource[int]{newClosingSource(1, 2, 3), newClosingSource(4, 5, 6)}
+	got := Merge[int](FromSource[int](sources[0]), FromSource[int](sources[1]), Repeat(0, -1)).Take(2).Collect()
+	if len(got) != 2 {
+		t.Errorf("Merge() = %v, want 2 elements", got)
+	}
+	for _, source := range sources {
+		source.waitClosed(t)
+	}
+}
+
+func TestMerge_ErrEarlyExit(t *testing.T) {
+	defer checkGoroutines(t)()
+
+	// Err is called while an upstream is still pulled, which is a race under -race if errs is read too early
+	got, err := Merge(FromSlice([]int{1}), Range(0, 100000, 1)).Take(3).CollectErr()
+	if len(got) != 3 || err != nil {
+		t.Errorf("Merge() = %v, %v, want 3 elements", got, err)
+	}
+
+	got, err = Merge(FromSource[int](newFailingSource[int](errBroken)), Range(0, 100000, 1)).Take(3).CollectErr()
+	if len(got) != 3 {
+		t.Errorf("Merge() = %v, want 3 elements", got)
+	}
+	if err != nil && err != errBroken {
+		t.Errorf("Merge() err = %v, want nil or %v", err, errBroken)
+	}
+}
+
+func TestMerge_Panic(t *testing.T) {
+	defer checkGoroutines(t)()
+
+	failing := Map(FromVar(1, 2), func(v int) int {
+		if v == 2 {
+			panic("failure")
+		}
+		return v
+	})
+	got := Merge(failing, FromVar(3)).
+		Catch(func(err error) int {
+			var panicErr *PanicError
+			if !errors.As(err, &panicErr) {
+				t.Errorf("Merge() error = %v, want PanicError", err)
+			}
+			return 0
+		}).Collect()
+	sort.Ints(got)
+	if want := []int{0, 1, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Merge() = %v, want %v", got, want)
+	}
+}
+
+func TestCombinators_Err(t *testing.T) {
+	failure := errors.New("failure")
+	failing := func() Stream[int] {
+		return FromSource[int](newFailingSource(failure, 1))
+	}
+	tests := []struct {
+		name string
+		s    Stream[int]
+		want []int
+	}{
+		{"Concat", Concat(failing(), FromVar(2)), []int{1}},
+		{"Interleave", Interleave(FromVar(2, 3), failing()), []int{2, 1, 3}},
+		{"RoundRobin", RoundRobin(failing(), FromVar(2, 3)), []int{1, 2}},
+		{"MergeSorted", MergeSorted(lessInt, failing(), FromVar(0, 2)), []int{0}},
+		{"Merge", Merge(failing(), FromVar(2)), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.s.CollectErr()
+			if !errors.Is(err, failure) {
+				t.Errorf("%s() error = %v, want %v", tt.name, err, failure)
+			}
+			if tt.want != nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%s() = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCombinators_Close(t *testing.T) {
+	tests := []struct {
+		name    string
+		combine func(...Stream[int]) Stream[int]
+	}{
+		{"Concat", Concat[int]},
+		{"Interleave", Interleave[int]},
+		{"RoundRobin", RoundRobin[int]},
+		{"MergeSorted", func(streams ...Stream[int]) Stream[int] {
+			return MergeSorted(lessInt, streams...)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sources := []*closingSource[int]{newClosingSource(1), newClosingSource(2, 3), newClosingSource(4, 5)}
+			tt.combine(FromSource[int](sources[0]), FromSource[int](sources[1]), FromSource[int](sources[2])).Take(1).Collect()
+			for i, source := range sources {
+				if source.Closed() != 1 {
+					t.Errorf("source %d closed %d times, want 1", i, source.Closed())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package stream
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNotFound is returned by Find and FindLast when no element matches the predicate.
+var ErrNotFound = errors.New("stream: not found")
+
+// Optional is a value which may be absent,
+// it is returned by the terminal operations which have nothing to return on an empty stream.
+type Optional[T any] struct {
+	value   T
+	present bool
+}
+
+// OptionalOf returns an Optional with the given value.
+func OptionalOf[T any](v T) Optional[T] {
+	return Optional[T]{value: v, present: true}
+}
+
+// EmptyOptional returns an Optional without a value.
+func EmptyOptional[T any]() Optional[T] {
+	return Optional[T]{}
+}
+
+// IsPresent returns true if the Optional has a value.
+func (o Optional[T]) IsPresent() bool {
+	return o.present
+}
+
+// Get returns the value and whether it is present, the value is the zero value if absent.
+func (o Optional[T]) Get() (T, bool) {
+	return o.value, o.present
+}
+
+// OrElse returns the value if present, otherwise other.
+func (o Optional[T]) OrElse(other T) T {
+	if o.present {
+		return o.value
+	}
+	return other
+}
+
+// OrElseGet returns the value if present, otherwise the result of supplier.
+func (o Optional[T]) OrElseGet(supplier func() T) T {
+	if o.present {
+		return o.value
+	}
+	return supplier()
+}
+
+// Map returns an Optional with the result of applying mapf to the value if present, otherwise an empty Optional.
+func (o Optional[T]) Map(mapf func(T) T) Optional[T] {
+	return MapOptional(o, mapf)
+}
+
+// Filter returns the Optional if the value is present and matches the predicate, otherwise an empty Optional.
+func (o Optional[T]) Filter(predicate func(T) bool) Optional[T] {
+	if o.present && predicate(o.value) {
+		return o
+	}
+	return Optional[T]{}
+}
+
+func (o Optional[T]) String() string {
+	if o.present {
+		return fmt.Sprintf("Optional[%v]", o.value)
+	}
+	return "Optional.Empty"
+}
+
+// MapOptional returns an Optional with the result of applying mapf to the value if present, otherwise an empty Optional.
+func MapOptional[T, U any](o Optional[T], mapf func(T) U) Optional[U] {
+	if o.present {
+		return OptionalOf(mapf(o.value))
+	}
+	return Optional[U]{}
+}
+
+// First returns the first element of this stream, or an empty Optional if the stream is empty.
+func (s *baseStream[T]) First() Optional[T] {
+	return s.FindFirst(func(T) bool {
+		return true
+	})
+}
+
+// Last returns the last element of this stream, or an empty Optional if the stream is empty.
+func (s *baseStream[T]) Last() Optional[T] {
+	if s == nil {
+		return Optional[T]{}
+	}
+	if onerror := s.getonrecover(); onerror != nil {
+		defer onerror()
+	}
+	defer s.close()
+
+	var last Optional[T]
+	for s.next() {
+		last = OptionalOf(s.get().(T))
+	}
+	return last
+}
+
+// FindFirst returns the first element of this stream matching the given predicate,
+// or an empty Optional if no such element exists.
+func (s *baseStream[T]) FindFirst(predicate func(T) bool) Optional[T] {
+	if s == nil {
+		return Optional[T]{}
+	}
+	if onerror := s.getonrecover(); onerror != nil {
+		defer onerror()
+	}
+	defer s.close()
+
+	for s.next() {
+		v := s.get().(T)
+		if predicate(v) {
+			return OptionalOf(v)
+		}
+	}
+	return Optional[T]{}
+}
+
+// Min returns the first smallest element of this stream by less, or an empty Optional if the stream is empty.
+func (s *baseStream[T]) Min(less func(a T, b T) bool) Optional[T] {
+	return s.ReduceOpt(func(acc T, ele T) T {
+		if less(ele, acc) {
+			return ele
+		}
+		return acc
+	})
+}
+
+// Max returns the first largest element of this stream by less, or an empty Optional if the stream is empty.
+func (s *baseStream[T]) Max(less func(a T, b T) bool) Optional[T] {
+	return s.ReduceOpt(func(acc T, ele T) T {
+		if less(acc, ele) {
+			return ele
+		}
+		return acc
+	})
+}
+
+// ReduceOpt performs a reduction on the elements of this stream like Reduce,
+// and returns an empty Optional if the stream is empty.
+// [a, b, c, d] => f(f(f(a, b), c), d)
+func (s *baseStream[T]) ReduceOpt(reducer func(acc T, ele T) T) Optional[T] {
+	if s == nil {
+		return Optional[T]{}
+	}
+	if onerror := s.getonrecover(); onerror != nil {
+		defer onerror()
+	}
+	defer s.close()
+
+	if !s.next() {
+		return Optional[T]{}
+	}
+	result := s.get().(T)
+	for s.next() {
+		result = reducer(result, s.get().(T))
+	}
+	return OptionalOf(result)
+}
@@ -0,0 +1,130 @@
+package stream
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestOptional(t *testing.T) {
+	present := OptionalOf(0)
+	empty := EmptyOptional[int]()
+
+	if !present.IsPresent() || empty.IsPresent() {
+		t.Errorf("IsPresent() = %v, %v, want true, false", present.IsPresent(), empty.IsPresent())
+	}
+	if v, ok := present.Get(); v != 0 || !ok {
+		t.Errorf("Get() = %v, %v, want 0, true", v, ok)
+	}
+	if got := present.OrElse(1); got != 0 {
+		t.Errorf("OrElse() = %v, want 0", got)
+	}
+	if got := empty.OrElse(1); got != 1 {
+		t.Errorf("OrElse() = %v, want 1", got)
+	}
+	if got := empty.OrElseGet(func() int { return 2 }); got != 2 {
+		t.Errorf("OrElseGet() = %v, want 2", got)
+	}
+	if got := present.OrElseGet(func() int { panic("should not be called") }); got != 0 {
+		t.Errorf("OrElseGet() = %v, want 0", got)
+	}
+	if got := present.Map(func(v int) int { return v + 1 }); got != OptionalOf(1) {
+		t.Errorf("Map() = %v, want %v", got, OptionalOf(1))
+	}
+	if got := empty.Map(func(v int) int { return v + 1 }); got.IsPresent() {
+		t.Errorf("Map() = %v, want empty", got)
+	}
+	if got := present.Filter(func(v int) bool { return v > 0 }); got.IsPresent() {
+		t.Errorf("Filter() = %v, want empty", got)
+	}
+	if got := present.Filter(func(v int) bool { return v == 0 }); got != present {
+		t.Errorf("Filter() = %v, want %v", got, present)
+	}
+	if got := MapOptional(OptionalOf(12), strconv.Itoa); got != OptionalOf("12") {
+		t.Errorf("MapOptional() = %v, want %v", got, OptionalOf("12"))
+	}
+	if got := present.String(); got != "Optional[0]" {
+		t.Errorf("String() = %v, want Optional[0]", got)
+	}
+}
+
+func TestStream_OptionalTerminals(t *testing.T) {
+	less := func(a, b int) bool {
+		return a < b
+	}
+	sum := func(acc, ele int) int {
+		return acc + ele
+	}
+	type testCase[T any] struct {
+		name string
+		got  func() Optional[T]
+		want Optional[T]
+	}
+	tests := []testCase[int]{
+		{"First", func() Optional[int] { return FromVar(0, 1, 2).First() }, OptionalOf(0)},
+		{"First empty", func() Optional[int] { return FromVar[int]().First() }, EmptyOptional[int]()},
+		{"Last", func() Optional[int] { return FromVar(1, 2, 0).Last() }, OptionalOf(0)},
+		{"Last empty", func() Optional[int] { return FromVar[int]().Last() }, EmptyOptional[int]()},
+		{"FindFirst", func() Optional[int] { return FromVar(1, 0, 2).FindFirst(equalTo(0)) }, OptionalOf(0)},
+		{"FindFirst none", func() Optional[int] { return FromVar(1, 2).FindFirst(equalTo(0)) }, EmptyOptional[int]()},
+		{"Min", func() Optional[int] { return FromVar(3, 0, 2, 0).Min(less) }, OptionalOf(0)},
+		{"Min empty", func() Optional[int] { return FromVar[int]().Min(less) }, EmptyOptional[int]()},
+		{"Max", func() Optional[int] { return FromVar(3, 5, 2).Max(less) }, OptionalOf(5)},
+		{"Max empty", func() Optional[int] { return FromVar[int]().Max(less) }, EmptyOptional[int]()},
+		{"ReduceOpt", func() Optional[int] { return FromVar(-1, 1).ReduceOpt(sum) }, OptionalOf(0)},
+		{"ReduceOpt empty", func() Optional[int] { return FromVar[int]().ReduceOpt(sum) }, EmptyOptional[int]()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.got(); got != tt.want {
+				t.Errorf("%s() = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStream_MinMaxFirst(t *testing.T) {
+	less := func(a, b sortItem) bool {
+		return a.Key < b.Key
+	}
+	items := []sortItem{{2, 0}, {1, 1}, {2, 2}, {1, 3}}
+	if got, _ := FromSlice(items).Min(less).Get(); !reflect.DeepEqual(got, sortItem{1, 1}) {
+		t.Errorf("Min() = %v, want the first smallest", got)
+	}
+	if got, _ := FromSlice(items).Max(less).Get(); !reflect.DeepEqual(got, sortItem{2, 0}) {
+		t.Errorf("Max() = %v, want the first largest", got)
+	}
+}
+
+func TestStream_FindNotFound(t *testing.T) {
+	type testCase[T any] struct {
+		name    string
+		find    func() (T, error)
+		want    T
+		wantErr error
+	}
+	failure := errors.New("failure")
+	tests := []testCase[int]{
+		{"Find zero", func() (int, error) { return FromVar(1, 0).Find(equalTo(0)) }, 0, nil},
+		{"Find none", func() (int, error) { return FromVar(1, 2).Find(equalTo(0)) }, 0, ErrNotFound},
+		{"Find empty", func() (int, error) { return FromVar[int]().Find(equalTo(0)) }, 0, ErrNotFound},
+		{"Find failed", func() (int, error) {
+			return FromSource[int](newFailingSource(failure, 1)).Find(equalTo(0))
+		}, 0, failure},
+		{"FindLast zero", func() (int, error) { return FromVar(0, 1, 0).FindLast(equalTo(0)) }, 0, nil},
+		{"FindLast", func() (int, error) { return FromVar(1, 2, 3).FindLast(lessThan(3)) }, 2, nil},
+		{"FindLast none", func() (int, error) { return FromVar(1, 2).FindLast(equalTo(0)) }, 0, ErrNotFound},
+		{"FindLast failed", func() (int, error) {
+			return FromSource[int](newFailingSource(failure, 0)).FindLast(equalTo(0))
+		}, 0, failure},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.find()
+			if got != tt.want || !errors.Is(err, tt.wantErr) {
+				t.Errorf("%s = %v, %v, want %v, %v", tt.name, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
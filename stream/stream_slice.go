@@ -0,0 +1,252 @@
+package stream
+
+//
+// slicing operations
+//
+// the index of an element of the resulting streams counts only the emitted elements,
+// so MapIndex and FindIndex downstream see the indexes from 0.
+//
+
+// TakeWhile returns a stream consisting of the longest prefix of this stream whose elements match the predicate,
+// the upstream is not pulled after the first element which does not match.
+//
+//	TakeWhile(x < 3) with [1, 2, 3, 1] produces [1, 2]
+func (s *baseStream[T]) TakeWhile(predicate func(T) bool) Stream[T] {
+	if s == nil {
+		return s
+	}
+	return s.takeUntil(func(v T) bool {
+		return !predicate(v)
+	}, false)
+}
+
+// TakeUntil returns a stream consisting of the elements of this stream until an element matches the predicate,
+// the matching element is included if inclusive is true.
+//
+//	TakeUntil(x == 3, false) with [1, 2, 3, 4] produces [1, 2]
+//	TakeUntil(x == 3, true) with [1, 2, 3, 4] produces [1, 2, 3]
+func (s *baseStream[T]) TakeUntil(predicate func(T) bool, inclusive bool) Stream[T] {
+	if s == nil {
+		return s
+	}
+	return s.takeUntil(predicate, inclusive)
+}
+
+type takeUntilStream[T any] struct {
+	baseStream[T]
+	cur  T
+	done bool
+}
+
+func (s *baseStream[T]) takeUntil(predicate func(T) bool, inclusive bool) Stream[T] {
+	takestream := new(takeUntilStream[T])
+	takestream.idx = -1
+	takestream.next = func() bool {
+		if takestream.done || !s.next() {
+			takestream.done = true
+			return false
+		}
+		v := s.get().(T)
+		if predicate(v) {
+			takestream.done = true
+			if !inclusive {
+				return false
+			}
+		}
+		takestream.idx++
+		takestream.cur = v
+		return true
+	}
+	takestream.get = func() any {
+		return takestream.cur
+	}
+	takestream.getonrecover = func() RecoverFunc {
+		return s.getonrecover()
+	}
+	takestream.geterr = func() error {
+		return s.geterr()
+	}
+	takestream.close = func() error {
+		return s.close()
+	}
+	return takestream
+}
+
+// DropWhile returns a stream consisting of the remaining elements of this stream
+// after dropping the longest prefix whose elements match the predicate.
+//
+//	DropWhile(x < 3) with [1, 2, 3, 1] produces [3, 1]
+func (s *baseStream[T]) DropWhile(predicate func(T) bool) Stream[T] {
+	if s == nil {
+		return s
+	}
+
+	dropping := true
+	dropstream := new(takeUntilStream[T])
+	dropstream.idx = -1
+	dropstream.next = func() bool {
+		for s.next() {
+			v := s.get().(T)
+			if dropping && predicate(v) {
+				continue
+			}
+			dropping = false
+			dropstream.idx++
+			dropstream.cur = v
+			return true
+		}
+		return false
+	}
+	dropstream.get = func() any {
+		return dropstream.cur
+	}
+	dropstream.getonrecover = func() RecoverFunc {
+		return s.getonrecover()
+	}
+	dropstream.geterr = func() error {
+		return s.geterr()
+	}
+	dropstream.close = func() error {
+		return s.close()
+	}
+	return dropstream
+}
+
+// ring is a ring buffer of the last elements.
+type ring[T any] struct {
+	buf   []T
+	start int
+	size  int
+}
+
+func newRing[T any](n int) *ring[T] {
+	return &ring[T]{buf: make([]T, n)}
+}
+
+// push appends the element, and returns the oldest element if the buffer is full.
+func (r *ring[T]) push(v T) (evicted T, ok bool) {
+	if len(r.buf) == 0 {
+		return v, true
+	}
+	if r.size < len(r.buf) {
+		r.buf[(r.start+r.size)%len(r.buf)] = v
+		r.size++
+		return evicted, false
+	}
+	evicted = r.buf[r.start]
+	r.buf[r.start] = v
+	r.start = (r.start + 1) % len(r.buf)
+	return evicted, true
+}
+
+// pop removes and returns the oldest element.
+func (r *ring[T]) pop() (v T, ok bool) {
+	if r.size == 0 {
+		return v, false
+	}
+	v = r.buf[r.start]
+	r.start = (r.start + 1) % len(r.buf)
+	r.size--
+	return v, true
+}
+
+// TakeLast returns a stream consisting of the last n elements of this stream,
+// which are kept in a ring buffer of n elements while the upstream is drained when the stream is pulled first.
+func (s *baseStream[T]) TakeLast(n int) Stream[T] {
+	if s == nil {
+		return s
+	}
+	if n < 0 {
+		n = 0
+	}
+
+	var last *ring[T]
+	takestream := new(takeUntilStream[T])
+	takestream.idx = -1
+	takestream.next = func() bool {
+		if last == nil {
+			last = newRing[T](n)
+			for s.next() {
+				last.push(s.get().(T))
+			}
+		}
+		v, ok := last.pop()
+		if !ok {
+			return false
+		}
+		takestream.idx++
+		takestream.cur = v
+		return true
+	}
+	takestream.get = func() any {
+		return takestream.cur
+	}
+	takestream.getonrecover = func() RecoverFunc {
+		return s.getonrecover()
+	}
+	takestream.geterr = func() error {
+		return s.geterr()
+	}
+	takestream.close = func() error {
+		return s.close()
+	}
+	return takestream
+}
+
+// SkipLast returns a stream consisting of the elements of this stream except the last n elements,
+// an element is emitted when n elements after it are pulled, so only n elements are kept in a ring buffer.
+func (s *baseStream[T]) SkipLast(n int) Stream[T] {
+	if s == nil {
+		return s
+	}
+	if n < 0 {
+		n = 0
+	}
+
+	delayed := newRing[T](n)
+	skipstream := new(takeUntilStream[T])
+	skipstream.idx = -1
+	skipstream.next = func() bool {
+		for s.next() {
+			if v, ok := delayed.push(s.get().(T)); ok {
+				skipstream.idx++
+				skipstream.cur = v
+				return true
+			}
+		}
+		return false
+	}
+	skipstream.get = func() any {
+		return skipstream.cur
+	}
+	skipstream.getonrecover = func() RecoverFunc {
+		return s.getonrecover()
+	}
+	skipstream.geterr = func() error {
+		return s.geterr()
+	}
+	skipstream.close = func() error {
+		return s.close()
+	}
+	return skipstream
+}
+
+// Slice returns a stream consisting of the elements of this stream from index from to index to exclusive,
+// or to the end if to is negative.
+//
+//	Slice(1, 3) with [a, b, c, d] produces [b, c]
+func (s *baseStream[T]) Slice(from int, to int) Stream[T] {
+	if s == nil {
+		return s
+	}
+	if from < 0 {
+		from = 0
+	}
+	if to < 0 {
+		return s.Skip(from)
+	}
+	if to < from {
+		to = from
+	}
+	return s.Skip(from).Take(to - from)
+}
@@ -0,0 +1,184 @@
+package stream
+
+import (
+	"reflect"
+	"testing"
+)
+
+func lessThan(n int) func(int) bool {
+	return func(ele int) bool {
+		return ele < n
+	}
+}
+
+func equalTo(n int) func(int) bool {
+	return func(ele int) bool {
+		return ele == n
+	}
+}
+
+func TestStream_TakeWhile(t *testing.T) {
+	type testCase[T any] struct {
+		name string
+		s    Stream[T]
+		want []T
+	}
+	tests := []testCase[int]{
+		{"empty", FromVar[int]().TakeWhile(lessThan(3)), []int{}},
+		{"prefix", FromVar(1, 2, 3, 1).TakeWhile(lessThan(3)), []int{1, 2}},
+		{"all", FromVar(1, 2).TakeWhile(lessThan(3)), []int{1, 2}},
+		{"none", FromVar(3, 1).TakeWhile(lessThan(3)), []int{}},
+		{"infinite", Iterate(0, func(v int) int { return v + 1 }).TakeWhile(lessThan(4)), []int{0, 1, 2, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.s.Collect(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("TakeWhile() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStream_TakeWhile_Pulls(t *testing.T) {
+	pulled := 0
+	FromVar(1, 2, 3, 4, 5).OnEach(func(int) {
+		pulled++
+	}).TakeWhile(lessThan(3)).Collect()
+	if pulled != 3 {
+		t.Errorf("TakeWhile() pulled %d elements, want 3", pulled)
+	}
+}
+
+func TestStream_TakeUntil(t *testing.T) {
+	type testCase[T any] struct {
+		name string
+		s    Stream[T]
+		want []T
+	}
+	tests := []testCase[int]{
+		{"exclusive", FromVar(1, 2, 3, 4).TakeUntil(equalTo(3), false), []int{1, 2}},
+		{"inclusive", FromVar(1, 2, 3, 4).TakeUntil(equalTo(3), true), []int{1, 2, 3}},
+		{"not found", FromVar(1, 2).TakeUntil(equalTo(3), true), []int{1, 2}},
+		{"first", FromVar(3, 4).TakeUntil(equalTo(3), true), []int{3}},
+		{"channel", FromChan(FromVar(1, 2, 3, 4).ToChan(0)).TakeUntil(equalTo(2), true), []int{1, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.s.Collect(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("TakeUntil() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStream_DropWhile(t *testing.T) {
+	type testCase[T any] struct {
+		name string
+		s    Stream[T]
+		want []T
+	}
+	tests := []testCase[int]{
+		{"empty", FromVar[int]().DropWhile(lessThan(3)), []int{}},
+		{"prefix", FromVar(1, 2, 3, 1).DropWhile(lessThan(3)), []int{3, 1}},
+		{"all", FromVar(1, 2).DropWhile(lessThan(3)), []int{}},
+		{"none", FromVar(3, 1).DropWhile(lessThan(3)), []int{3, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.s.Collect(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DropWhile() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStream_TakeLast(t *testing.T) {
+	type testCase[T any] struct {
+		name string
+		s    Stream[T]
+		want []T
+	}
+	tests := []testCase[int]{
+		{"empty", FromVar[int]().TakeLast(2), []int{}},
+		{"last", FromVar(1, 2, 3, 4, 5).TakeLast(2), []int{4, 5}},
+		{"longer", FromVar(1, 2).TakeLast(5), []int{1, 2}},
+		{"zero", FromVar(1, 2).TakeLast(0), []int{}},
+		{"wrap", Range(0, 100, 1).TakeLast(3), []int{97, 98, 99}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.s.Collect(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("TakeLast() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStream_SkipLast(t *testing.T) {
+	type testCase[T any] struct {
+		name string
+		s    Stream[T]
+		want []T
+	}
+	tests := []testCase[int]{
+		{"empty", FromVar[int]().SkipLast(2), []int{}},
+		{"skip", FromVar(1, 2, 3, 4, 5).SkipLast(2), []int{1, 2, 3}},
+		{"longer", FromVar(1, 2).SkipLast(5), []int{}},
+		{"zero", FromVar(1, 2).SkipLast(0), []int{1, 2}},
+		{"wrap", Range(0, 100, 1).SkipLast(97), []int{0, 1, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.s.Collect(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SkipLast() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStream_Slice(t *testing.T) {
+	type testCase[T any] struct {
+		name string
+		s    Stream[T]
+		want []T
+	}
+	tests := []testCase[string]{
+		{"middle", FromVar("a", "b", "c", "d").Slice(1, 3), []string{"b", "c"}},
+		{"to end", FromVar("a", "b", "c", "d").Slice(2, -1), []string{"c", "d"}},
+		{"beyond", FromVar("a", "b").Slice(1, 5), []string{"b"}},
+		{"empty", FromVar("a", "b").Slice(2, 1), []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.s.Collect(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Slice() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSlicing_Index(t *testing.T) {
+	withIndex := func(i int, ele int) [2]int {
+		return [2]int{i, ele}
+	}
+	want := [][2]int{{0, 3}, {1, 4}}
+
+	streams := map[string]Stream[int]{
+		"DropWhile": FromVar(1, 2, 3, 4).DropWhile(lessThan(3)),
+		"TakeLast":  FromVar(1, 2, 3, 4).TakeLast(2),
+		"SkipLast":  FromVar(3, 4, 5).SkipLast(1),
+		"Slice":     FromVar(1, 2, 3, 4, 5).Slice(2, 4),
+		"TakeWhile": FromVar(3, 4, 5).TakeWhile(lessThan(5)),
+	}
+	for name, s := range streams {
+		t.Run(name, func(t *testing.T) {
+			if got := MapIndex(s, withIndex).Collect(); !reflect.DeepEqual(got, want) {
+				t.Errorf("MapIndex() = %v, want %v", got, want)
+			}
+		})
+	}
+
+	if got := FromVar(1, 2, 3, 4).DropWhile(lessThan(3)).FindIndex(equalTo(4)); got != 1 {
+		t.Errorf("FindIndex() = %v, want 1", got)
+	}
+}
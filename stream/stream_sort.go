@@ -0,0 +1,513 @@
+package stream
+
+import (
+	"bufio"
+	"container/heap"
+	"io"
+	"os"
+	"sort"
+)
+
+type sortStream[T any] struct {
+	baseStream[T]
+	sorted []T
+	loaded bool
+	err    error
+}
+
+// newSortStream returns a stream of the elements given by load, which is called when the stream is pulled first.
+func newSortStream[T any](up *baseStream[T], load func() ([]T, error)) *sortStream[T] {
+	sortstream := new(sortStream[T])
+	sortstream.idx = -1
+	sortstream.next = func() bool {
+		if !sortstream.loaded {
+			sortstream.loaded = true
+			sortstream.sorted, sortstream.err = load()
+		}
+		if sortstream.idx+1 >= len(sortstream.sorted) {
+			return false
+		}
+		sortstream.idx++
+		return true
+	}
+	sortstream.get = func() any {
+		return sortstream.sorted[sortstream.idx]
+	}
+	sortstream.getonrecover = func() RecoverFunc {
+		return up.getonrecover()
+	}
+	sortstream.geterr = func() error {
+		if err := up.geterr(); err != nil {
+			return err
+		}
+		return sortstream.err
+	}
+	sortstream.close = func() error {
+		return up.close()
+	}
+	return sortstream
+}
+
+// Sorted returns a stream consisting of the elements of this stream sorted by less.
+// the sort is stable, and all the elements are buffered when the stream is pulled first.
+func (s *baseStream[T]) Sorted(less func(a T, b T) bool) Stream[T] {
+	if s == nil {
+		return s
+	}
+
+	return newSortStream(s, func() ([]T, error) {
+		var sorted []T
+		for s.next() {
+			sorted = append(sorted, s.get().(T))
+		}
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return less(sorted[i], sorted[j])
+		})
+		return sorted, nil
+	})
+}
+
+// SortedBy returns a stream consisting of the elements of the stream sorted by the key returned by keyf.
+// the sort is stable, and the key is computed once for each element.
+func SortedBy[T any, K Ordered](s Stream[T], keyf func(T) K) Stream[T] {
+	up := toBase[T](s)
+	if up == nil {
+		var nilstream *baseStream[T]
+		return nilstream
+	}
+
+	return newSortStream(up, func() ([]T, error) {
+		var sorted []T
+		var keys []K
+		for up.next() {
+			v := up.get().(T)
+			sorted = append(sorted, v)
+			keys = append(keys, keyf(v))
+		}
+		sort.Stable(byKey[T, K]{sorted, keys})
+		return sorted, nil
+	})
+}
+
+type byKey[T any, K Ordered] struct {
+	values []T
+	keys   []K
+}
+
+func (b byKey[T, K]) Len() int {
+	return len(b.values)
+}
+
+func (b byKey[T, K]) Less(i, j int) bool {
+	return b.keys[i] < b.keys[j]
+}
+
+func (b byKey[T, K]) Swap(i, j int) {
+	b.values[i], b.values[j] = b.values[j], b.values[i]
+	b.keys[i], b.keys[j] = b.keys[j], b.keys[i]
+}
+
+// topItem is an element of a topHeap with its arrival order.
+type topItem[T any] struct {
+	value T
+	seq   int
+}
+
+// topHeap is a heap of which root is the largest by less, the later element is larger in a tie to keep TopK stable.
+type topHeap[T any] struct {
+	items []topItem[T]
+	less  func(a T, b T) bool
+}
+
+func (h *topHeap[T]) Len() int {
+	return len(h.items)
+}
+
+func (h *topHeap[T]) Less(i, j int) bool {
+	a, b := h.items[i], h.items[j]
+	if h.less(b.value, a.value) {
+		return true
+	}
+	if h.less(a.value, b.value) {
+		return false
+	}
+	return a.seq > b.seq
+}
+
+func (h *topHeap[T]) Swap(i, j int) {
+	h.items[i], h.items[j] = h.items[j], h.items[i]
+}
+
+func (h *topHeap[T]) Push(v any) {
+	h.items = append(h.items, v.(topItem[T]))
+}
+
+func (h *topHeap[T]) Pop() any {
+	last := len(h.items) - 1
+	v := h.items[last]
+	h.items = h.items[:last]
+	return v
+}
+
+// TopK returns a stream consisting of the first k elements of this stream in the order of less,
+// which is same as Sorted(less).Take(k) but keeps only k elements in memory.
+// use a reversed less for the k largest elements.
+func (s *baseStream[T]) TopK(k int, less func(a T, b T) bool) Stream[T] {
+	if s == nil {
+		return s
+	}
+
+	return newSortStream(s, func() ([]T, error) {
+		if k <= 0 {
+			for s.next() {
+			}
+			return nil, nil
+		}
+		h := &topHeap[T]{less: less}
+		for seq := 0; s.next(); seq++ {
+			v := s.get().(T)
+			if h.Len() < k {
+				heap.Push(h, topItem[T]{v, seq})
+			} else if less(v, h.items[0].value) {
+				// an equal element arrived later, so it is dropped
+				h.items[0] = topItem[T]{v, seq}
+				heap.Fix(h, 0)
+			}
+		}
+		sorted := make([]T, h.Len())
+		for i := len(sorted) - 1; i >= 0; i-- {
+			sorted[i] = heap.Pop(h).(topItem[T]).value
+		}
+		return sorted, nil
+	})
+}
+
+//
+// external sort
+//
+
+// maxMergeFanIn is the maximum number of the runs merged at once, which bounds the open files of SortedExternal.
+const maxMergeFanIn = 64
+
+// spillRun is a sorted run spilled to a temporary file, which is open only while the run is merged.
+type spillRun[T any] struct {
+	name string
+	file *os.File
+	dec  Decoder[T]
+}
+
+// spill writes the values returned by next to a new run with the codec, until next returns false.
+func spill[T any](codec Codec[T], next func() (T, bool, error)) (run *spillRun[T], err error) {
+	file, err := os.CreateTemp("", "go-stream-sort-*")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if closeerr := file.Close(); err == nil {
+			err = closeerr
+		}
+		if err != nil {
+			os.Remove(file.Name())
+			run = nil
+		}
+	}()
+
+	w := bufio.NewWriter(file)
+	enc := codec.NewEncoder(w)
+	for {
+		v, ok, err := next()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			break
+		}
+		if err = enc.Encode(v); err != nil {
+			return nil, err
+		}
+	}
+	if err = w.Flush(); err != nil {
+		return nil, err
+	}
+	return &spillRun[T]{name: file.Name()}, nil
+}
+
+func (r *spillRun[T]) open(codec Codec[T]) error {
+	file, err := os.Open(r.name)
+	if err != nil {
+		return err
+	}
+	r.file = file
+	r.dec = codec.NewDecoder(bufio.NewReader(file))
+	return nil
+}
+
+// close closes and removes the file of the run.
+func (r *spillRun[T]) close() {
+	if r.file != nil {
+		r.file.Close()
+		r.file = nil
+	}
+	os.Remove(r.name)
+}
+
+type mergeItem[T any] struct {
+	value T
+	run   int
+}
+
+// mergeHeap is a heap of the heads of the runs, the earlier run wins a tie to keep the sort stable.
+type mergeHeap[T any] struct {
+	items []mergeItem[T]
+	less  func(a T, b T) bool
+}
+
+func (h *mergeHeap[T]) Len() int {
+	return len(h.items)
+}
+
+func (h *mergeHeap[T]) Less(i, j int) bool {
+	a, b := h.items[i], h.items[j]
+	if h.less(a.value, b.value) {
+		return true
+	}
+	if h.less(b.value, a.value) {
+		return false
+	}
+	return a.run < b.run
+}
+
+func (h *mergeHeap[T]) Swap(i, j int) {
+	h.items[i], h.items[j] = h.items[j], h.items[i]
+}
+
+func (h *mergeHeap[T]) Push(v any) {
+	h.items = append(h.items, v.(mergeItem[T]))
+}
+
+func (h *mergeHeap[T]) Pop() any {
+	last := len(h.items) - 1
+	v := h.items[last]
+	h.items = h.items[:last]
+	return v
+}
+
+// runMerger merges the runs by a heap of their heads.
+type runMerger[T any] struct {
+	runs []*spillRun[T]
+	heap *mergeHeap[T]
+}
+
+// newRunMerger opens the runs and reads their heads, the runs are closed by the caller.
+func newRunMerger[T any](runs []*spillRun[T], less func(a T, b T) bool, codec Codec[T]) (*runMerger[T], error) {
+	m := &runMerger[T]{
+		runs: runs,
+		heap: &mergeHeap[T]{less: less},
+	}
+	for i, run := range runs {
+		if err := run.open(codec); err != nil {
+			return nil, err
+		}
+		v, err := run.dec.Decode()
+		if err == io.EOF {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		m.heap.items = append(m.heap.items, mergeItem[T]{v, i})
+	}
+	heap.Init(m.heap)
+	return m, nil
+}
+
+// pop returns the smallest head of the runs, and reads the next of the run.
+func (m *runMerger[T]) pop() (v T, ok bool, err error) {
+	h := m.heap
+	if h.Len() == 0 {
+		return v, false, nil
+	}
+	item := h.items[0]
+	next, err := m.runs[item.run].dec.Decode()
+	if err == io.EOF {
+		heap.Pop(h)
+	} else if err != nil {
+		return v, false, err
+	} else {
+		h.items[0] = mergeItem[T]{next, item.run}
+		heap.Fix(h, 0)
+	}
+	return item.value, true, nil
+}
+
+type externalSortStream[T any] struct {
+	baseStream[T]
+	runs   []*spillRun[T]
+	merger *runMerger[T]
+	sorted []T // the elements sorted in memory if no run is spilled
+	cur    T
+	loaded bool
+	err    error
+}
+
+// SortedExternal returns a stream consisting of the elements of the stream sorted by less,
+// which keeps at most budget elements in memory.
+// the elements are sorted in memory like Sorted if they are within the budget.
+// when the budget is exceeded, the buffered elements are sorted and spilled to a temporary file with the codec,
+// and the spilled runs are merged when the stream is pulled.
+// a run is a file of budget elements, and at most 64 of them are open at once:
+// more runs are merged into longer runs in several passes, each of which rewrites all the elements.
+// the temporary files are removed when the stream ends, and an error of the files is reported by Err.
+func SortedExternal[T any](s Stream[T], less func(a T, b T) bool, budget int, codec Codec[T]) Stream[T] {
+	up := toBase[T](s)
+	if up == nil {
+		var nilstream *baseStream[T]
+		return nilstream
+	}
+	if budget < 1 {
+		budget = 1
+	}
+
+	sortstream := new(externalSortStream[T])
+	sortruns := func() error {
+		buf := make([]T, 0, budget)
+		flush := func() error {
+			sort.SliceStable(buf, func(i, j int) bool {
+				return less(buf[i], buf[j])
+			})
+			i := 0
+			run, err := spill(codec, func() (v T, ok bool, err error) {
+				if i == len(buf) {
+					return v, false, nil
+				}
+				i++
+				return buf[i-1], true, nil
+			})
+			if err != nil {
+				return err
+			}
+			sortstream.runs = append(sortstream.runs, run)
+			buf = buf[:0]
+			return nil
+		}
+		for up.next() {
+			if len(buf) == budget {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+			buf = append(buf, up.get().(T))
+		}
+		if len(sortstream.runs) == 0 {
+			// within the budget, the elements are sorted in memory like Sorted
+			sort.SliceStable(buf, func(i, j int) bool {
+				return less(buf[i], buf[j])
+			})
+			sortstream.sorted = buf
+			return nil
+		}
+		if len(buf) > 0 {
+			return flush()
+		}
+		return nil
+	}
+	// mergepass merges each maxMergeFanIn runs into a run, the merged runs stay in order to keep the sort stable.
+	mergepass := func() error {
+		runs := sortstream.runs
+		var merged []*spillRun[T]
+		for start := 0; start < len(runs); start += maxMergeFanIn {
+			end := start + maxMergeFanIn
+			if end > len(runs) {
+				end = len(runs)
+			}
+			group := runs[start:end]
+			m, err := newRunMerger(group, less, codec)
+			var run *spillRun[T]
+			if err == nil {
+				run, err = spill(codec, m.pop)
+			}
+			for _, r := range group {
+				r.close()
+			}
+			if err != nil {
+				sortstream.runs = append(merged, runs[end:]...)
+				return err
+			}
+			merged = append(merged, run)
+		}
+		sortstream.runs = merged
+		return nil
+	}
+	cleanup := func() {
+		for _, run := range sortstream.runs {
+			run.close()
+		}
+		sortstream.runs = nil
+		sortstream.merger = nil
+		sortstream.sorted = nil
+	}
+
+	sortstream.idx = -1
+	sortstream.next = func() bool {
+		if sortstream.err != nil {
+			return false
+		}
+		if !sortstream.loaded {
+			sortstream.loaded = true
+			if sortstream.err = sortruns(); sortstream.err != nil {
+				cleanup()
+				return false
+			}
+			for len(sortstream.runs) > maxMergeFanIn {
+				if sortstream.err = mergepass(); sortstream.err != nil {
+					cleanup()
+					return false
+				}
+			}
+			if len(sortstream.runs) > 0 {
+				if sortstream.merger, sortstream.err = newRunMerger(sortstream.runs, less, codec); sortstream.err != nil {
+					cleanup()
+					return false
+				}
+			}
+		}
+
+		var v T
+		if sortstream.merger != nil {
+			var ok bool
+			var err error
+			if v, ok, err = sortstream.merger.pop(); !ok {
+				sortstream.err = err
+				cleanup()
+				return false
+			}
+		} else if len(sortstream.sorted) > 0 {
+			v = sortstream.sorted[0]
+			sortstream.sorted = sortstream.sorted[1:]
+		} else {
+			return false
+		}
+		sortstream.idx++
+		sortstream.cur = v
+		return true
+	}
+	sortstream.get = func() any {
+		return sortstream.cur
+	}
+	sortstream.getonrecover = func() RecoverFunc {
+		return up.getonrecover()
+	}
+	sortstream.geterr = func() error {
+		if err := up.geterr(); err != nil {
+			return err
+		}
+		return sortstream.err
+	}
+	sortstream.close = func() error {
+		// the stream ends with the runs removed
+		sortstream.loaded = true
+		cleanup()
+		return up.close()
+	}
+	return sortstream
+}
@@ -0,0 +1,303 @@
+package stream
+
+import (
+	"errors"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+type sortItem struct {
+	Key   int
+	Order int
+}
+
+func lessInt(a int, b int) bool {
+	return a < b
+}
+
+func TestStream_Sorted(t *testing.T) {
+	type testCase[T any] struct {
+		name string
+		s    Stream[T]
+		less func(a, b T) bool
+		want []T
+	}
+	tests := []testCase[int]{
+		{
+			name: "empty",
+			s:    FromVar[int](),
+			less: lessInt,
+			want: []int{},
+		},
+		{
+			name: "ascending",
+			s:    FromVar(3, 1, 2, 5, 4),
+			less: lessInt,
+			want: []int{1, 2, 3, 4, 5},
+		},
+		{
+			name: "descending",
+			s:    FromVar(3, 1, 2, 5, 4),
+			less: func(a, b int) bool { return a > b },
+			want: []int{5, 4, 3, 2, 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.s.Sorted(tt.less).Collect(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Sorted() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStream_Sorted_Stable(t *testing.T) {
+	items := []sortItem{{2, 0}, {1, 1}, {2, 2}, {1, 3}, {0, 4}}
+	want := []sortItem{{0, 4}, {1, 1}, {1, 3}, {2, 0}, {2, 2}}
+
+	got := FromSlice(items).Sorted(func(a, b sortItem) bool {
+		return a.Key < b.Key
+	}).Collect()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Sorted() = %v, want %v", got, want)
+	}
+
+	got = SortedBy(FromSlice(items), func(ele sortItem) int {
+		return ele.Key
+	}).Collect()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SortedBy() = %v, want %v", got, want)
+	}
+}
+
+func TestSortedBy(t *testing.T) {
+	got := SortedBy(FromVar("ccc", "a", "bb"), func(ele string) int {
+		return len(ele)
+	}).Collect()
+	if want := []string{"a", "bb", "ccc"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SortedBy() = %v, want %v", got, want)
+	}
+
+	got = SortedBy(FromVar("b", "c", "a"), func(ele string) string {
+		return ele
+	}).Collect()
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SortedBy() = %v, want %v", got, want)
+	}
+}
+
+func TestStream_TopK(t *testing.T) {
+	type testCase[T any] struct {
+		name string
+		s    Stream[T]
+		k    int
+		less func(a, b T) bool
+		want []T
+	}
+	tests := []testCase[int]{
+		{
+			name: "smallest",
+			s:    FromVar(5, 1, 4, 2, 3),
+			k:    3,
+			less: lessInt,
+			want: []int{1, 2, 3},
+		},
+		{
+			name: "largest",
+			s:    FromVar(5, 1, 4, 2, 3),
+			k:    2,
+			less: func(a, b int) bool { return a > b },
+			want: []int{5, 4},
+		},
+		{
+			name: "k larger than stream",
+			s:    FromVar(2, 3, 1),
+			k:    5,
+			less: lessInt,
+			want: []int{1, 2, 3},
+		},
+		{
+			name: "zero",
+			s:    FromVar(2, 3, 1),
+			k:    0,
+			less: lessInt,
+			want: []int{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.s.TopK(tt.k, tt.less).Collect(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("TopK() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStream_TopK_Random(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	nums := make([]int, 1000)
+	for i := range nums {
+		nums[i] = rnd.Intn(100)
+	}
+	want := append([]int{}, nums...)
+	sort.Ints(want)
+
+	got := FromSlice(nums).TopK(10, lessInt).Collect()
+	if !reflect.DeepEqual(got, want[:10]) {
+		t.Errorf("TopK() = %v, want %v", got, want[:10])
+	}
+}
+
+func TestStream_TopK_Stable(t *testing.T) {
+	lessKey := func(a, b sortItem) bool {
+		return a.Key < b.Key
+	}
+	items := []sortItem{{1, 0}, {0, 1}, {1, 2}, {1, 3}, {1, 4}, {0, 5}}
+	got := FromSlice(items).TopK(4, lessKey).Collect()
+	if want := []sortItem{{0, 1}, {0, 5}, {1, 0}, {1, 2}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("TopK() = %v, want %v", got, want)
+	}
+
+	rnd := rand.New(rand.NewSource(1))
+	items = make([]sortItem, 1000)
+	for i := range items {
+		items[i] = sortItem{rnd.Intn(5), i}
+	}
+	want := FromSlice(items).Sorted(lessKey).Take(50).Collect()
+	if got := FromSlice(items).TopK(50, lessKey).Collect(); !reflect.DeepEqual(got, want) {
+		t.Errorf("TopK() = %v, want %v", got, want)
+	}
+}
+
+// spillDir makes the temporary files of the external sort in a directory of the test.
+func spillDir(t *testing.T) string {
+	dir := t.TempDir()
+	t.Setenv("TMPDIR", dir)
+	return dir
+}
+
+func TestSortedExternal(t *testing.T) {
+	dir := spillDir(t)
+	rnd := rand.New(rand.NewSource(1))
+	items := make([]sortItem, 1000)
+	for i := range items {
+		items[i] = sortItem{rnd.Intn(50), i}
+	}
+	want := append([]sortItem{}, items...)
+	sort.SliceStable(want, func(i, j int) bool {
+		return want[i].Key < want[j].Key
+	})
+
+	tests := []struct {
+		name   string
+		budget int
+	}{
+		{"in memory", 2000},
+		{"at the budget", 1000},
+		{"two runs", 600},
+		{"many runs", 64},
+		{"one element", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SortedExternal(FromSlice(items), func(a, b sortItem) bool {
+				return a.Key < b.Key
+			}, tt.budget, GobCodec[sortItem]()).CollectErr()
+			if err != nil {
+				t.Fatalf("SortedExternal() err = %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("SortedExternal() = %v, want %v", got, want)
+			}
+			if files, _ := os.ReadDir(dir); len(files) != 0 {
+				t.Errorf("SortedExternal() left %d files", len(files))
+			}
+		})
+	}
+}
+
+func TestSortedExternal_OpenFiles(t *testing.T) {
+	spillDir(t)
+	fds, err := os.ReadDir("/proc/self/fd")
+	if err != nil {
+		t.Skip("open files are not known:", err)
+	}
+
+	sorted := SortedExternal[int](Range(5000, 0, -1), lessInt, 1, GobCodec[int]())
+	if !sorted.Next() || sorted.Get() != 1 {
+		t.Fatalf("SortedExternal() first = %v, want 1", sorted.Get())
+	}
+	open, _ := os.ReadDir("/proc/self/fd")
+	if n := len(open) - len(fds); n > maxMergeFanIn {
+		t.Errorf("SortedExternal() opened %d files, want at most %d", n, maxMergeFanIn)
+	}
+	got := []int{1}
+	for sorted.Next() {
+		got = append(got, sorted.Get())
+	}
+	if want := Range(1, 5001, 1).Collect(); !reflect.DeepEqual(got, want) || sorted.Err() != nil {
+		t.Errorf("SortedExternal() = %d elements, %v, want %d", len(got), sorted.Err(), len(want))
+	}
+}
+
+func TestSortedExternal_UpstreamError(t *testing.T) {
+	spillDir(t)
+
+	got, err := SortedExternal(FromSource[int](newFailingSource(errBroken, 3, 1, 2)), lessInt, 2, GobCodec[int]()).CollectErr()
+	if want := []int{1, 2, 3}; !reflect.DeepEqual(got, want) || err != errBroken {
+		t.Errorf("SortedExternal() = %v, %v, want %v, %v", got, err, want, errBroken)
+	}
+}
+
+type brokenCodec[T any] struct{}
+
+func (brokenCodec[T]) NewEncoder(w io.Writer) Encoder[T] {
+	return brokenEncoder[T]{}
+}
+
+func (brokenCodec[T]) NewDecoder(r io.Reader) Decoder[T] {
+	return GobCodec[T]().NewDecoder(r)
+}
+
+type brokenEncoder[T any] struct{}
+
+func (brokenEncoder[T]) Encode(v T) error {
+	return errBroken
+}
+
+func TestSortedExternal_InMemory(t *testing.T) {
+	dir := spillDir(t)
+
+	// nothing is encoded within the budget
+	sorted := SortedExternal[int](FromVar(3, 1, 2), lessInt, 3, brokenCodec[int]{})
+	if !sorted.Next() || sorted.Get() != 1 {
+		t.Fatalf("SortedExternal() first = %v, want 1", sorted.Get())
+	}
+	if files, _ := os.ReadDir(dir); len(files) != 0 {
+		t.Errorf("SortedExternal() created %d files within the budget", len(files))
+	}
+	got := []int{1}
+	for sorted.Next() {
+		got = append(got, sorted.Get())
+	}
+	if want := []int{1, 2, 3}; !reflect.DeepEqual(got, want) || sorted.Err() != nil {
+		t.Errorf("SortedExternal() = %v, %v, want %v", got, sorted.Err(), want)
+	}
+}
+
+func TestSortedExternal_SpillError(t *testing.T) {
+	dir := spillDir(t)
+
+	got, err := SortedExternal[int](FromVar(3, 1, 2), lessInt, 2, brokenCodec[int]{}).CollectErr()
+	if len(got) != 0 || !errors.Is(err, errBroken) {
+		t.Errorf("SortedExternal() = %v, %v, want [], %v", got, err, errBroken)
+	}
+	if files, _ := filepath.Glob(filepath.Join(dir, "*")); len(files) != 0 {
+		t.Errorf("SortedExternal() left %v", files)
+	}
+}
@@ -1,6 +1,7 @@
 package stream
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 )
@@ -104,3 +105,141 @@ func TestFromSource_Indexed(t *testing.T) {
 		})
 	}
 }
+
+var errBroken = errors.New("broken")
+
+// failingSource fails with err after producing the given elements
+type failingSource[T any] struct {
+	index    int
+	elements []T
+	err      error
+}
+
+func newFailingSource[T any](err error, elements ...T) *failingSource[T] {
+	return &failingSource[T]{
+		index:    -1,
+		elements: elements,
+		err:      err,
+	}
+}
+
+func (c *failingSource[T]) Next() bool {
+	if c.index+1 == len(c.elements) {
+		return false
+	}
+	c.index++
+	return true
+}
+
+func (c *failingSource[T]) Get() T {
+	return c.elements[c.index]
+}
+
+func (c *failingSource[T]) Err() error {
+	if c.index+1 == len(c.elements) {
+		return c.err
+	}
+	return nil
+}
+
+func TestFromSource_Err(t *testing.T) {
+	type testCase[T any] struct {
+		name    string
+		s       Stream[T]
+		want    []T
+		wantErr error
+	}
+	tests := []testCase[int]{
+		{
+			name:    "no error",
+			s:       FromSource[int](newFailingSource(nil, 1, 2, 3)),
+			want:    []int{1, 2, 3},
+			wantErr: nil,
+		},
+		{
+			name:    "error",
+			s:       FromSource[int](newFailingSource(errBroken, 1, 2, 3)),
+			want:    []int{1, 2, 3},
+			wantErr: errBroken,
+		},
+		{
+			name: "filter map",
+			s: FromSource[int](newFailingSource(errBroken, 1, 2, 3)).
+				Filter(func(ele int) bool {
+					return ele != 2
+				}).
+				Map(func(ele int) int {
+					return ele * 10
+				}),
+			want:    []int{10, 30},
+			wantErr: errBroken,
+		},
+		{
+			name: "flatmapconcat inner",
+			s: FromVar(1, 2, 3).FlatMapConcat(func(ele int) Source[int] {
+				if ele == 2 {
+					return newFailingSource(errBroken, ele)
+				}
+				return FromVar(ele)
+			}),
+			want:    []int{1, 2},
+			wantErr: errBroken,
+		},
+		{
+			name: "zipwith other",
+			s: FromVar(1, 2, 3).ZipWith(newFailingSource(errBroken, 10), func(a, b int) int {
+				return a + b
+			}),
+			want:    []int{11},
+			wantErr: errBroken,
+		},
+		{
+			name: "typed map",
+			s: Map(FromSource[int](newFailingSource(errBroken, 1, 2)), func(ele int) int {
+				return ele + 1
+			}),
+			want:    []int{2, 3},
+			wantErr: errBroken,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.s.CollectErr()
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CollectErr() = %v, want %v", got, tt.want)
+			}
+			if err != tt.wantErr {
+				t.Errorf("CollectErr() err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFromSource_ForEachErr(t *testing.T) {
+	var visited []int
+	err := FromSource[int](newFailingSource(errBroken, 1, 2)).ForEachErr(func(ele int) {
+		visited = append(visited, ele)
+	})
+	if err != errBroken {
+		t.Errorf("ForEachErr() = %v, want %v", err, errBroken)
+	}
+	if !reflect.DeepEqual(visited, []int{1, 2}) {
+		t.Errorf("ForEachErr() visited %v", visited)
+	}
+}
+
+func TestFromSource_FoldErr(t *testing.T) {
+	result, err := FromSource[int](newFailingSource(errBroken, 1, 2, 3)).FoldErr(0, func(acc, ele int) int {
+		return acc + ele
+	})
+	if result != 6 || err != errBroken {
+		t.Errorf("FoldErr() = %v, %v, want %v, %v", result, err, 6, errBroken)
+	}
+
+	length, err := FoldErr(FromSource[string](newFailingSource(errBroken, "a", "bb")), 0, func(acc int, ele string) int {
+		return acc + len(ele)
+	})
+	if length != 3 || err != errBroken {
+		t.Errorf("FoldErr() = %v, %v, want %v, %v", length, err, 3, errBroken)
+	}
+}
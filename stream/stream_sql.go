@@ -0,0 +1,267 @@
+package stream
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Queryer runs a query, which is implemented by *sql.DB, *sql.Conn and *sql.Tx.
+type Queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+var mapRowType = reflect.TypeOf(map[string]any(nil))
+
+// newRowScanner returns a function which scans the current row of the rows into T.
+// T is a struct, a pointer to a struct or map[string]any.
+// the columns are mapped to the fields by `db:"column"` tags or the names of the fields case-insensitively,
+// and the columns which are not mapped are discarded.
+func newRowScanner[T any](rows *sql.Rows) (func() (T, error), error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	t := reflect.TypeOf((*T)(nil)).Elem()
+
+	if t == mapRowType {
+		return func() (v T, err error) {
+			values := make([]any, len(columns))
+			dests := make([]any, len(columns))
+			for i := range values {
+				dests[i] = &values[i]
+			}
+			if err = rows.Scan(dests...); err != nil {
+				return v, err
+			}
+			row := make(map[string]any, len(columns))
+			for i, column := range columns {
+				row[column] = values[i]
+			}
+			return any(row).(T), nil
+		}, nil
+	}
+
+	pointer := t.Kind() == reflect.Pointer
+	if pointer {
+		t = t.Elem()
+	}
+	fields, err := columnFields(t, "db")
+	if err != nil {
+		return nil, err
+	}
+	byColumn := map[string]int{}
+	for _, f := range fields {
+		byColumn[strings.ToLower(f.column)] = f.index
+	}
+	indexes := make([]int, len(columns))
+	for i, column := range columns {
+		index, ok := byColumn[strings.ToLower(column)]
+		if !ok {
+			index = -1
+		}
+		indexes[i] = index
+	}
+
+	return func() (v T, err error) {
+		rv := reflect.New(t)
+		dests := make([]any, len(columns))
+		for i, index := range indexes {
+			if index < 0 {
+				dests[i] = new(any)
+				continue
+			}
+			dests[i] = rv.Elem().Field(index).Addr().Interface()
+		}
+		if err = rows.Scan(dests...); err != nil {
+			return v, err
+		}
+		if pointer {
+			return rv.Interface().(T), nil
+		}
+		return rv.Elem().Interface().(T), nil
+	}, nil
+}
+
+// fromRows returns a stream of the rows, which are opened by open when the stream is pulled first if rows is nil.
+func fromRows[T any](rows *sql.Rows, open func() (*sql.Rows, error)) Stream[T] {
+	stream := new(baseStream[T])
+	stream.idx = -1
+	var scan func() (T, error)
+	var v T
+	var err error
+	var done bool
+	stream.next = func() bool {
+		if done {
+			return false
+		}
+		if scan == nil {
+			if rows == nil {
+				if rows, err = open(); err != nil {
+					done = true
+					return false
+				}
+			}
+			if scan, err = newRowScanner[T](rows); err != nil {
+				done = true
+				rows.Close()
+				return false
+			}
+		}
+		if !rows.Next() {
+			done = true
+			err = rows.Err()
+			if cerr := rows.Close(); err == nil {
+				err = cerr
+			}
+			return false
+		}
+		if v, err = scan(); err != nil {
+			done = true
+			err = fmt.Errorf("stream: scan row %d: %w", stream.idx+1, err)
+			rows.Close()
+			return false
+		}
+		stream.idx++
+		return true
+	}
+
+	stream.get = func() any {
+		return v
+	}
+	stream.getonrecover = func() RecoverFunc {
+		return nil
+	}
+	stream.geterr = func() error {
+		return err
+	}
+	stream.close = func() error {
+		done = true
+		if rows == nil {
+			return nil
+		}
+		return rows.Close()
+	}
+	return stream
+}
+
+// FromRows build a Stream of the rows scanned into T, which is a struct, a pointer to a struct or map[string]any.
+// the columns are mapped to the fields by `db:"column"` tags or the names of the fields case-insensitively.
+// the rows are closed when the stream ends, or when the terminal operation returns early or panics.
+// an error of the rows or scanning a row ends the stream and is reported by Err.
+func FromRows[T any](rows *sql.Rows) Stream[T] {
+	if rows == nil {
+		return FromVar[T]()
+	}
+	return fromRows[T](rows, nil)
+}
+
+// FromQuery build a Stream of the rows of the query scanned into T like FromRows.
+// the query runs when the stream is pulled first, and an error of the query is reported by Err.
+func FromQuery[T any](ctx context.Context, db Queryer, query string, args ...any) Stream[T] {
+	return fromRows[T](nil, func() (*sql.Rows, error) {
+		return db.QueryContext(ctx, query, args...)
+	})
+}
+
+// TxBeginner begins a transaction, which is implemented by *sql.DB and *sql.Conn.
+type TxBeginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// BatchError is an error of a batch of ToSQL, the batch has been rolled back.
+type BatchError struct {
+	Batch  int // index of the batch
+	Offset int // index of the first element of the batch in the stream
+	Size   int // number of the elements of the batch
+	Err    error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("stream: batch %d of %d elements from %d: %v", e.Batch, e.Size, e.Offset, e.Err)
+}
+
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// execBatch executes the statement with the arguments of each element in a transaction.
+// the transaction is rolled back if any of the elements fails or argsf panics.
+func execBatch[T any](ctx context.Context, db TxBeginner, insertStmt string, argsf func(T) []any, batch []T) (err error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			tx.Rollback()
+		}
+	}()
+
+	stmt, err := tx.PrepareContext(ctx, insertStmt)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	for _, v := range batch {
+		if _, err = stmt.ExecContext(ctx, argsf(v)...); err != nil {
+			return err
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+	committed = true
+	return nil
+}
+
+// ToSQL writes the elements of the stream to the database in batches of batchSize elements,
+// each batch is executed in a transaction with the insertStmt prepared, and argsf gives the arguments of an element.
+// it returns the number of the elements written by the committed batches,
+// and stops at the first failed batch with a *BatchError, or returns the error of the stream.
+// the elements pulled before the stream fails are written.
+func ToSQL[T any](ctx context.Context, s Stream[T], db TxBeginner, insertStmt string, argsf func(T) []any, batchSize int) (written int, err error) {
+	up := toBase[T](s)
+	if up == nil {
+		return 0, nil
+	}
+	if onerror := up.getonrecover(); onerror != nil {
+		defer onerror()
+	}
+	defer up.close()
+	if batchSize < 1 {
+		batchSize = 1
+	}
+
+	batch := make([]T, 0, batchSize)
+	flush := func() error {
+		if err := execBatch(ctx, db, insertStmt, argsf, batch); err != nil {
+			return &BatchError{
+				Batch:  written / batchSize,
+				Offset: written,
+				Size:   len(batch),
+				Err:    err,
+			}
+		}
+		written += len(batch)
+		batch = batch[:0]
+		return nil
+	}
+	for up.next() {
+		batch = append(batch, up.get().(T))
+		if len(batch) == batchSize {
+			if err := flush(); err != nil {
+				return written, err
+			}
+		}
+	}
+	if len(batch) > 0 {
+		if err := flush(); err != nil {
+			return written, err
+		}
+	}
+	return written, up.geterr()
+}
@@ -0,0 +1,284 @@
+package stream
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+type sqlItem struct {
+	ID     int            `db:"id"`
+	Name   string         `db:"name"`
+	Score  float64        // mapped by the name of the field
+	Note   sql.NullString `db:"note"`
+	Ignore string         `db:"-"`
+}
+
+// openTestDB opens an in-memory database with a single connection,
+// so a connection which is not released by the rows is visible with Stats.
+func openTestDB(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() {
+		db.Close()
+	})
+
+	for _, stmt := range []string{
+		`CREATE TABLE items (id INTEGER PRIMARY KEY, name TEXT, score REAL, note TEXT)`,
+		`INSERT INTO items VALUES (1, 'a', 1.5, 'x'), (2, 'b', 2.5, NULL), (3, 'c', 3.5, 'z')`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return db
+}
+
+func assertReleased(t *testing.T, db *sql.DB) {
+	t.Helper()
+	if inuse := db.Stats().InUse; inuse != 0 {
+		t.Errorf("%d connections in use, want 0", inuse)
+	}
+}
+
+func TestFromQuery(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+
+	got, err := FromQuery[sqlItem](ctx, db, `SELECT * FROM items ORDER BY id`).CollectErr()
+	want := []sqlItem{
+		{1, "a", 1.5, sql.NullString{String: "x", Valid: true}, ""},
+		{2, "b", 2.5, sql.NullString{}, ""},
+		{3, "c", 3.5, sql.NullString{String: "z", Valid: true}, ""},
+	}
+	if !reflect.DeepEqual(got, want) || err != nil {
+		t.Errorf("FromQuery() = %v, %v, want %v", got, err, want)
+	}
+	assertReleased(t, db)
+
+	ptrs, err := FromQuery[*sqlItem](ctx, db, `SELECT name, id, 'extra' AS extra FROM items WHERE id > ?`, 2).CollectErr()
+	if len(ptrs) != 1 || *ptrs[0] != (sqlItem{ID: 3, Name: "c"}) || err != nil {
+		t.Errorf("FromQuery() = %v, %v, want [&{3 c}]", ptrs, err)
+	}
+	assertReleased(t, db)
+}
+
+func TestFromQuery_Map(t *testing.T) {
+	db := openTestDB(t)
+
+	got, err := FromQuery[map[string]any](context.Background(), db, `SELECT id, note FROM items WHERE id <= 2 ORDER BY id`).CollectErr()
+	want := []map[string]any{
+		{"id": int64(1), "note": "x"},
+		{"id": int64(2), "note": nil},
+	}
+	if !reflect.DeepEqual(got, want) || err != nil {
+		t.Errorf("FromQuery() = %v, %v, want %v", got, err, want)
+	}
+	assertReleased(t, db)
+}
+
+func TestFromQuery_Error(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+
+	got, err := FromQuery[sqlItem](ctx, db, `SELECT * FROM nothing`).CollectErr()
+	if len(got) != 0 || err == nil {
+		t.Errorf("FromQuery() = %v, %v, want an error of the query", got, err)
+	}
+
+	got, err = FromQuery[sqlItem](ctx, db, `SELECT name AS id FROM items ORDER BY id`).CollectErr()
+	if len(got) != 0 || err == nil {
+		t.Errorf("FromQuery() = %v, %v, want an error of the scan", got, err)
+	}
+	assertReleased(t, db)
+
+	_, err = FromQuery[int](ctx, db, `SELECT id FROM items`).CollectErr()
+	if err == nil {
+		t.Errorf("FromQuery() err = nil, want an error for a non struct")
+	}
+	assertReleased(t, db)
+}
+
+func TestFromRows_Close(t *testing.T) {
+	db := openTestDB(t)
+
+	tests := []struct {
+		name     string
+		terminal func(s Stream[sqlItem])
+	}{
+		{
+			name: "take",
+			terminal: func(s Stream[sqlItem]) {
+				s.Take(1).Collect()
+			},
+		},
+		{
+			name: "find",
+			terminal: func(s Stream[sqlItem]) {
+				s.Find(func(ele sqlItem) bool {
+					return ele.ID == 1
+				})
+			},
+		},
+		{
+			name: "panic",
+			terminal: func(s Stream[sqlItem]) {
+				defer func() {
+					recover()
+				}()
+				s.ForEach(func(ele sqlItem) {
+					panic(errBroken)
+				})
+			},
+		},
+		{
+			name: "not pulled",
+			terminal: func(s Stream[sqlItem]) {
+				s.Take(0).Count()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := db.Query(`SELECT * FROM items ORDER BY id`)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.terminal(FromRows[sqlItem](rows))
+			assertReleased(t, db)
+		})
+	}
+}
+
+func TestFromQuery_Canceled(t *testing.T) {
+	db := openTestDB(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := FromQuery[sqlItem](ctx, db, `SELECT * FROM items`).CollectErr()
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("FromQuery() err = %v, want %v", err, context.Canceled)
+	}
+}
+
+func sqlItemArgs(ele sqlItem) []any {
+	return []any{ele.ID, ele.Name, ele.Score, ele.Note}
+}
+
+func countItems(t *testing.T, db *sql.DB) (count int) {
+	t.Helper()
+	if err := db.QueryRow(`SELECT COUNT(*) FROM items`).Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	return count
+}
+
+func TestToSQL(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	items := make([]sqlItem, 7)
+	for i := range items {
+		items[i] = sqlItem{ID: 10 + i, Name: "n"}
+	}
+
+	written, err := ToSQL(ctx, FromSlice(items), db, `INSERT INTO items VALUES (?, ?, ?, ?)`, sqlItemArgs, 3)
+	if written != 7 || err != nil {
+		t.Errorf("ToSQL() = %v, %v, want 7", written, err)
+	}
+	if count := countItems(t, db); count != 10 {
+		t.Errorf("ToSQL() wrote %d items, want 10", count)
+	}
+	assertReleased(t, db)
+}
+
+func TestToSQL_BatchError(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	// the 5th element conflicts with the existing id 1
+	items := []sqlItem{{ID: 10}, {ID: 11}, {ID: 12}, {ID: 13}, {ID: 1}, {ID: 15}, {ID: 16}}
+
+	written, err := ToSQL(ctx, FromSlice(items), db, `INSERT INTO items VALUES (?, ?, ?, ?)`, sqlItemArgs, 3)
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("ToSQL() err = %v, want BatchError", err)
+	}
+	if want := (BatchError{Batch: 1, Offset: 3, Size: 3, Err: batchErr.Err}); *batchErr != want || batchErr.Err == nil {
+		t.Errorf("ToSQL() err = %+v, want %+v", *batchErr, want)
+	}
+	if written != 3 {
+		t.Errorf("ToSQL() written = %v, want 3", written)
+	}
+	if count := countItems(t, db); count != 6 {
+		t.Errorf("ToSQL() wrote %d items, want 6 with the failed batch rolled back", count)
+	}
+	assertReleased(t, db)
+}
+
+func TestToSQL_StreamError(t *testing.T) {
+	db := openTestDB(t)
+
+	written, err := ToSQL(context.Background(), FromSource[sqlItem](newFailingSource(errBroken, sqlItem{ID: 10}, sqlItem{ID: 11})),
+		db, `INSERT INTO items VALUES (?, ?, ?, ?)`, sqlItemArgs, 5)
+	if written != 2 || err != errBroken {
+		t.Errorf("ToSQL() = %v, %v, want 2, %v", written, err, errBroken)
+	}
+	if count := countItems(t, db); count != 5 {
+		t.Errorf("ToSQL() wrote %d items, want 5", count)
+	}
+	assertReleased(t, db)
+}
+
+func TestToSQL_Panic(t *testing.T) {
+	db := openTestDB(t)
+
+	func() {
+		defer func() {
+			recover()
+		}()
+		ToSQL(context.Background(), FromVar(sqlItem{ID: 10}, sqlItem{ID: 11}), db, `INSERT INTO items VALUES (?, ?, ?, ?)`, func(ele sqlItem) []any {
+			if ele.ID == 11 {
+				panic(errBroken)
+			}
+			return sqlItemArgs(ele)
+		}, 5)
+	}()
+	if count := countItems(t, db); count != 3 {
+		t.Errorf("ToSQL() wrote %d items, want 3 with the batch rolled back", count)
+	}
+	assertReleased(t, db)
+}
+
+func TestToSQL_FromQuery(t *testing.T) {
+	source := openTestDB(t)
+	target := openTestDB(t)
+	ctx := context.Background()
+
+	upper := Map(FromQuery[sqlItem](ctx, source, `SELECT * FROM items ORDER BY id`), func(ele sqlItem) sqlItem {
+		ele.ID += 100
+		ele.Name = ele.Name + ele.Name
+		return ele
+	})
+	written, err := ToSQL(ctx, upper, target, `INSERT INTO items VALUES (?, ?, ?, ?)`, sqlItemArgs, 2)
+	if written != 3 || err != nil {
+		t.Fatalf("ToSQL() = %v, %v, want 3", written, err)
+	}
+
+	got := FromQuery[sqlItem](ctx, target, `SELECT * FROM items WHERE id > 100 ORDER BY id`).Collect()
+	want := []sqlItem{
+		{101, "aa", 1.5, sql.NullString{String: "x", Valid: true}, ""},
+		{102, "bb", 2.5, sql.NullString{}, ""},
+		{103, "cc", 3.5, sql.NullString{String: "z", Valid: true}, ""},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FromQuery() = %v, want %v", got, want)
+	}
+	assertReleased(t, source)
+	assertReleased(t, target)
+}
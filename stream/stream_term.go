@@ -5,6 +5,7 @@ func CollectAs[T any](s Source[any]) (target []T) {
 	if s == nil {
 		return []T{}
 	}
+	defer closeSource(s)
 
 	target = []T{}
 	for s.Next() {
@@ -15,16 +16,25 @@ func CollectAs[T any](s Source[any]) (target []T) {
 }
 
 // CollectAsSafe returns a slice containing the elements of this stream,
-// and error handler will be installed to handle any error
-func CollectAsSafe[T any](s Source[any], onerror func()) (target []T) {
+// it recovers from a panic and returns the elements collected so far with the error.
+// the error of the source is returned if the source is an ErrSource.
+func CollectAsSafe[T any](s Source[any]) (target []T, err error) {
+	target = []T{}
 	if s == nil {
-		return []T{}
-	}
-	if onerror != nil {
-		defer onerror()
+		return target, nil
 	}
+	defer closeSource(s)
+	defer func() {
+		if r := recover(); r != nil {
+			err = recoverErr(r)
+		}
+	}()
 
-	return CollectAs[T](s)
+	for s.Next() {
+		v := s.Get()
+		target = append(target, v.(T))
+	}
+	return target, sourceErr(s)
 }
 
 // CollectTo returns a slice containing the elements of this stream into target slice
@@ -32,6 +42,7 @@ func CollectTo[T any](s Source[any], target []T) []T {
 	if s == nil {
 		return target
 	}
+	defer closeSource(s)
 
 	for s.Next() {
 		v := s.Get()
@@ -41,17 +52,25 @@ func CollectTo[T any](s Source[any], target []T) []T {
 }
 
 // CollectToSafe returns a slice containing the elements of this stream into target slice,
-// and error handler will be installed to handle any error
-func CollectToSafe[T any](s Source[any], target []T, onerror func()) []T {
+// it recovers from a panic and returns the elements collected so far with the error.
+// the error of the source is returned if the source is an ErrSource.
+func CollectToSafe[T any](s Source[any], target []T) (result []T, err error) {
+	result = target
 	if s == nil {
-		return target
+		return result, nil
 	}
+	defer closeSource(s)
+	defer func() {
+		if r := recover(); r != nil {
+			err = recoverErr(r)
+		}
+	}()
 
-	if onerror != nil {
-		defer onerror()
+	for s.Next() {
+		v := s.Get()
+		result = append(result, v.(T))
 	}
-
-	return CollectTo(s, target)
+	return result, sourceErr(s)
 }
 
 // ForEachAs performs an action for each element of this stream.
@@ -59,6 +78,7 @@ func ForEachAs[T any](s Source[any], f func(T)) {
 	if s == nil {
 		return
 	}
+	defer closeSource(s)
 
 	for s.Next() {
 		f(s.Get().(T))
@@ -70,6 +90,7 @@ func ForEachIndex[T any](s Source[any], visit func(int, T)) {
 	if s == nil {
 		return
 	}
+	defer closeSource(s)
 
 	idx := 0
 	for s.Next() {
@@ -86,6 +107,7 @@ func ReduceAs[T any](s Source[any], accumf func(T, T) T) T {
 	if s == nil {
 		return result
 	}
+	defer closeSource(s)
 
 	for s.Next() {
 		v := s.Get()
@@ -98,6 +120,7 @@ func FindOrAs[T any](s Source[any], predicate func(T) bool, defvalue T) T {
 	if s == nil {
 		return defvalue
 	}
+	defer closeSource(s)
 
 	for s.Next() {
 		v := s.Get().(T)
@@ -0,0 +1,46 @@
+package stream
+
+import (
+	"fmt"
+)
+
+// Pair is a tuple of two values of possibly different types.
+type Pair[A, B any] struct {
+	First  A
+	Second B
+}
+
+// PairOf returns a Pair of the given values.
+func PairOf[A, B any](first A, second B) Pair[A, B] {
+	return Pair[A, B]{First: first, Second: second}
+}
+
+// Values returns the values of the Pair.
+func (p Pair[A, B]) Values() (A, B) {
+	return p.First, p.Second
+}
+
+func (p Pair[A, B]) String() string {
+	return fmt.Sprintf("(%v, %v)", p.First, p.Second)
+}
+
+// Triple is a tuple of three values of possibly different types.
+type Triple[A, B, C any] struct {
+	First  A
+	Second B
+	Third  C
+}
+
+// TripleOf returns a Triple of the given values.
+func TripleOf[A, B, C any](first A, second B, third C) Triple[A, B, C] {
+	return Triple[A, B, C]{First: first, Second: second, Third: third}
+}
+
+// Values returns the values of the Triple.
+func (t Triple[A, B, C]) Values() (A, B, C) {
+	return t.First, t.Second, t.Third
+}
+
+func (t Triple[A, B, C]) String() string {
+	return fmt.Sprintf("(%v, %v, %v)", t.First, t.Second, t.Third)
+}
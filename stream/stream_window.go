@@ -0,0 +1,508 @@
+package stream
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// PartialWindow is a policy for the trailing windows which have less elements than the window size.
+type PartialWindow int
+
+const (
+	// PartialEmit emits the trailing partial windows as they are.
+	PartialEmit PartialWindow = iota
+	// PartialDrop drops the trailing partial windows.
+	PartialDrop
+	// PartialPad pads the trailing partial windows with the zero value up to the window size.
+	PartialPad
+)
+
+// windower slices the upstream into windows.
+type windower[T any] struct {
+	up      *baseStream[T]
+	size    int
+	step    int
+	partial PartialWindow
+	buf     []T
+	skip    int // elements to skip before the next window when step > size
+	done    bool
+}
+
+func (w *windower[T]) next() (window []T, ok bool) {
+	for len(w.buf) < w.size && !w.done {
+		if !w.up.next() {
+			w.done = true
+			break
+		}
+		if w.skip > 0 {
+			w.skip--
+			continue
+		}
+		w.buf = append(w.buf, w.up.get().(T))
+	}
+	if len(w.buf) == 0 {
+		return nil, false
+	}
+
+	if len(w.buf) < w.size {
+		switch w.partial {
+		case PartialDrop:
+			w.buf = nil
+			return nil, false
+		case PartialPad:
+			window = make([]T, w.size)
+		default:
+			window = make([]T, len(w.buf))
+		}
+	} else {
+		window = make([]T, w.size)
+	}
+	copy(window, w.buf)
+
+	if w.step < len(w.buf) {
+		w.buf = w.buf[w.step:]
+	} else {
+		w.skip = w.step - len(w.buf)
+		w.buf = nil
+	}
+	return window, true
+}
+
+// newWindowStream returns a stream of windows, the windows are of type W which is []T or any.
+func newWindowStream[T, W any](up *baseStream[T], size int, step int, partial PartialWindow) *baseStream[W] {
+	if size < 1 {
+		size = 1
+	}
+	if step < 1 {
+		step = 1
+	}
+
+	win := &windower[T]{
+		up:      up,
+		size:    size,
+		step:    step,
+		partial: partial,
+	}
+	var cur []T
+	windowstream := new(baseStream[W])
+	windowstream.idx = -1
+	windowstream.next = func() bool {
+		window, ok := win.next()
+		if ok {
+			windowstream.idx++
+			cur = window
+		}
+		return ok
+	}
+	windowstream.get = func() any {
+		return cur
+	}
+	windowstream.getonrecover = func() RecoverFunc {
+		return up.getonrecover()
+	}
+	windowstream.geterr = func() error {
+		return up.geterr()
+	}
+	windowstream.close = func() error {
+		return up.close()
+	}
+	return windowstream
+}
+
+// Chunk returns a stream consisting of the tumbling windows of n elements of the stream.
+// the last window has less than n elements if the stream is not divided by n.
+//
+//	with source=[1, 2, 3, 4, 5] and n=2 produces [[1, 2], [3, 4], [5]]
+func Chunk[T any](s Stream[T], n int) Stream[[]T] {
+	up := toBase[T](s)
+	if up == nil {
+		var nilstream *baseStream[[]T]
+		return nilstream
+	}
+	return newWindowStream[T, []T](up, n, n, PartialEmit)
+}
+
+// Window returns a stream consisting of the windows of size elements of the stream,
+// a new window starts every step elements, which are
+// sliding windows if step < size, tumbling windows if step == size and hopping windows if step > size.
+// the trailing windows which have less than size elements are handled by the partial policy.
+//
+//	with source=[1, 2, 3, 4], size=3, step=1 and PartialEmit produces [[1, 2, 3], [2, 3, 4], [3, 4], [4]]
+//	with source=[1, 2, 3, 4], size=3, step=1 and PartialDrop produces [[1, 2, 3], [2, 3, 4]]
+//	with source=[1, 2, 3, 4], size=3, step=2 and PartialPad produces [[1, 2, 3], [3, 4, 0]]
+func Window[T any](s Stream[T], size int, step int, partial PartialWindow) Stream[[]T] {
+	up := toBase[T](s)
+	if up == nil {
+		var nilstream *baseStream[[]T]
+		return nilstream
+	}
+	return newWindowStream[T, []T](up, size, step, partial)
+}
+
+// ChunkAny returns a stream consisting of the tumbling windows of n elements of this stream.
+// the elements of the stream are []T.
+func (s *baseStream[T]) ChunkAny(n int) Stream[any] {
+	if s == nil {
+		return nilAnyStream
+	}
+	return newWindowStream[T, any](s, n, n, PartialEmit)
+}
+
+// WindowAny returns a stream consisting of the windows of size elements of this stream,
+// a new window starts every step elements.
+// the elements of the stream are []T.
+func (s *baseStream[T]) WindowAny(size int, step int, partial PartialWindow) Stream[any] {
+	if s == nil {
+		return nilAnyStream
+	}
+	return newWindowStream[T, any](s, size, step, partial)
+}
+
+//
+// time windows
+//
+// the upstream is pulled by a goroutine, and each element is stamped with the time of the clock when it is pulled.
+// the windows are decided by the stamped time, not by when the element is delivered,
+// so the windows are deterministic with a ManualClock advanced by the source.
+//
+
+type timedValue[T any] struct {
+	value T
+	time  time.Time
+	err   error // recovered from a panic of the upstream
+}
+
+// pumpTimed pulls the upstream in a goroutine and sends the elements stamped with the time of the clock.
+// the pump exits when the upstream ends or stop is called, and closes the upstream on its goroutine,
+// so the upstream is never pulled and closed concurrently.
+func pumpTimed[T any](up *baseStream[T], clock Clock) (elements <-chan timedValue[T], stop func()) {
+	ch := make(chan timedValue[T])
+	stopped := make(chan struct{})
+	go func() {
+		defer close(ch)
+		defer up.close()
+		defer func() {
+			if r := recover(); r != nil {
+				select {
+				case ch <- timedValue[T]{err: recoverErr(r)}:
+				case <-stopped:
+				}
+			}
+		}()
+
+		for up.next() {
+			now := clock.Now()
+			select {
+			case ch <- timedValue[T]{value: up.get().(T), time: now}:
+			case <-stopped:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			close(stopped)
+		})
+	}
+}
+
+// invalidWindow returns a stream which fails with an error for a duration which is not positive,
+// the upstream is not pulled and is closed when the stream is closed.
+func invalidWindow[T, W any](up *baseStream[T], name string, d time.Duration) Stream[W] {
+	err := fmt.Errorf("stream: %s must be positive, got %v", name, d)
+	windowstream := new(baseStream[W])
+	windowstream.idx = -1
+	windowstream.next = func() bool {
+		return false
+	}
+	windowstream.get = func() any {
+		return nil
+	}
+	windowstream.getonrecover = func() RecoverFunc {
+		return up.getonrecover()
+	}
+	windowstream.geterr = func() error {
+		return err
+	}
+	windowstream.close = func() error {
+		return up.close()
+	}
+	return windowstream
+}
+
+func values[T any](timed []timedValue[T]) []T {
+	result := make([]T, len(timed))
+	for i, v := range timed {
+		result[i] = v.value
+	}
+	return result
+}
+
+// SlidingWindowByTime returns a stream consisting of the windows of the elements of the stream
+// which arrived within size duration, a window is emitted every duration from when the stream is pulled first.
+// empty windows are not emitted, and the elements which have not been emitted are emitted when the stream ends.
+// the default clock is SystemClock if clock is nil.
+// the stream fails with an error reported by Err if size or every is not positive.
+//
+//	with size=2s and every=1s, the window at t=3s has the elements arrived in [1s, 3s).
+func SlidingWindowByTime[T any](s Stream[T], size time.Duration, every time.Duration, clock Clock) Stream[[]T] {
+	up := toBase[T](s)
+	if up == nil {
+		var nilstream *baseStream[[]T]
+		return nilstream
+	}
+	if size <= 0 {
+		return invalidWindow[T, []T](up, "window size", size)
+	}
+	if every <= 0 {
+		return invalidWindow[T, []T](up, "window period", every)
+	}
+	clock = clockOrDefault(clock)
+
+	var elements <-chan timedValue[T]
+	var stop func()
+	var buf []timedValue[T]
+	var ready [][]T
+	var nextTick time.Time
+	var timer Timer
+	var done bool
+
+	// evict drops the elements which are out of the window closing at the tick.
+	evict := func(tick time.Time) {
+		start := tick.Add(-size)
+		i := 0
+		for i < len(buf) && buf[i].time.Before(start) {
+			i++
+		}
+		buf = buf[i:]
+	}
+	// tick emits the windows closing until now.
+	tick := func(now time.Time) {
+		for !nextTick.After(now) {
+			evict(nextTick)
+			if len(buf) == 0 {
+				// skip the empty windows
+				nextTick = nextTick.Add((now.Sub(nextTick)/every + 1) * every)
+				break
+			}
+			var window []timedValue[T]
+			for _, v := range buf {
+				if !v.time.Before(nextTick) {
+					break
+				}
+				window = append(window, v)
+			}
+			if len(window) > 0 {
+				ready = append(ready, values(window))
+			}
+			nextTick = nextTick.Add(every)
+		}
+		if timer != nil {
+			timer.Stop()
+			timer = nil
+		}
+	}
+	// flush emits the elements which have not been emitted.
+	flush := func() {
+		evict(nextTick)
+		last := nextTick.Add(-every)
+		for _, v := range buf {
+			if !v.time.Before(last) {
+				ready = append(ready, values(buf))
+				break
+			}
+		}
+		buf = nil
+	}
+
+	var cur []T
+	windowstream := new(baseStream[[]T])
+	windowstream.idx = -1
+	windowstream.next = func() bool {
+		if elements == nil {
+			nextTick = clock.Now().Add(every)
+			elements, stop = pumpTimed(up, clock)
+		}
+		for len(ready) == 0 {
+			if done {
+				return false
+			}
+			if timer == nil {
+				timer = clock.NewTimer(nextTick.Sub(clock.Now()))
+			}
+			select {
+			case v, ok := <-elements:
+				if !ok {
+					timer.Stop()
+					done = true
+					flush()
+					continue
+				}
+				if v.err != nil {
+					panic(v.err)
+				}
+				if !v.time.Before(nextTick) {
+					tick(v.time)
+				}
+				buf = append(buf, v)
+			case <-timer.C():
+				timer = nil
+				tick(clock.Now())
+			}
+		}
+		windowstream.idx++
+		cur = ready[0]
+		ready = ready[1:]
+		return true
+	}
+	windowstream.get = func() any {
+		return cur
+	}
+	windowstream.getonrecover = func() RecoverFunc {
+		return up.getonrecover()
+	}
+	windowstream.geterr = func() error {
+		if !done {
+			return nil
+		}
+		return up.geterr()
+	}
+	windowstream.close = func() error {
+		done = true
+		if timer != nil {
+			timer.Stop()
+			timer = nil
+		}
+		if elements == nil {
+			return up.close()
+		}
+		// the upstream is closed by the pump
+		stop()
+		return nil
+	}
+	return windowstream
+}
+
+// WindowByTime returns a stream consisting of the tumbling windows of d duration of the stream,
+// which are [t0, t0+d), [t0+d, t0+2d) and so on from t0 when the stream is pulled first.
+// empty windows are not emitted, and the last window is emitted when the stream ends.
+// the default clock is SystemClock if clock is nil.
+// the stream fails with an error reported by Err if d is not positive.
+func WindowByTime[T any](s Stream[T], d time.Duration, clock Clock) Stream[[]T] {
+	return SlidingWindowByTime(s, d, d, clock)
+}
+
+// SessionWindow returns a stream consisting of the session windows of the stream.
+// a session window is closed when no element arrives within gap duration after the last element of the window,
+// and the last window is emitted when the stream ends.
+// the default clock is SystemClock if clock is nil.
+// the stream fails with an error reported by Err if gap is not positive.
+func SessionWindow[T any](s Stream[T], gap time.Duration, clock Clock) Stream[[]T] {
+	up := toBase[T](s)
+	if up == nil {
+		var nilstream *baseStream[[]T]
+		return nilstream
+	}
+	if gap <= 0 {
+		return invalidWindow[T, []T](up, "session gap", gap)
+	}
+	clock = clockOrDefault(clock)
+
+	var elements <-chan timedValue[T]
+	var stop func()
+	var buf []T
+	var ready [][]T
+	var deadline time.Time
+	var timer Timer
+	var done bool
+
+	closeWindow := func() {
+		ready = append(ready, buf)
+		buf = nil
+		if timer != nil {
+			timer.Stop()
+			timer = nil
+		}
+	}
+
+	var cur []T
+	windowstream := new(baseStream[[]T])
+	windowstream.idx = -1
+	windowstream.next = func() bool {
+		if elements == nil {
+			elements, stop = pumpTimed(up, clock)
+		}
+		for len(ready) == 0 {
+			if done {
+				return false
+			}
+			var timeout <-chan time.Time
+			if len(buf) > 0 {
+				if timer == nil {
+					timer = clock.NewTimer(deadline.Sub(clock.Now()))
+				}
+				timeout = timer.C()
+			}
+			select {
+			case v, ok := <-elements:
+				if !ok {
+					done = true
+					if len(buf) > 0 {
+						closeWindow()
+					}
+					continue
+				}
+				if v.err != nil {
+					panic(v.err)
+				}
+				if len(buf) > 0 && !v.time.Before(deadline) {
+					closeWindow()
+				}
+				buf = append(buf, v.value)
+				deadline = v.time.Add(gap)
+				if timer != nil {
+					timer.Stop()
+					timer = nil
+				}
+			case <-timeout:
+				timer = nil
+				if !clock.Now().Before(deadline) {
+					closeWindow()
+				}
+			}
+		}
+		windowstream.idx++
+		cur = ready[0]
+		ready = ready[1:]
+		return true
+	}
+	windowstream.get = func() any {
+		return cur
+	}
+	windowstream.getonrecover = func() RecoverFunc {
+		return up.getonrecover()
+	}
+	windowstream.geterr = func() error {
+		if !done {
+			return nil
+		}
+		return up.geterr()
+	}
+	windowstream.close = func() error {
+		done = true
+		if timer != nil {
+			timer.Stop()
+			timer = nil
+		}
+		if elements == nil {
+			return up.close()
+		}
+		// the upstream is closed by the pump
+		stop()
+		return nil
+	}
+	return windowstream
+}
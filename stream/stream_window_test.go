@@ -0,0 +1,300 @@
+package stream
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestChunk(t *testing.T) {
+	type testCase[T any] struct {
+		name string
+		s    Stream[T]
+		n    int
+		want [][]T
+	}
+	tests := []testCase[int]{
+		{
+			name: "empty",
+			s:    FromVar[int](),
+			n:    2,
+			want: [][]int{},
+		},
+		{
+			name: "divided",
+			s:    FromVar(1, 2, 3, 4),
+			n:    2,
+			want: [][]int{{1, 2}, {3, 4}},
+		},
+		{
+			name: "partial",
+			s:    FromVar(1, 2, 3, 4, 5),
+			n:    2,
+			want: [][]int{{1, 2}, {3, 4}, {5}},
+		},
+		{
+			name: "larger than stream",
+			s:    FromVar(1, 2, 3),
+			n:    5,
+			want: [][]int{{1, 2, 3}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Chunk(tt.s, tt.n).Collect(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Chunk() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWindow(t *testing.T) {
+	type args struct {
+		size    int
+		step    int
+		partial PartialWindow
+	}
+	type testCase[T any] struct {
+		name string
+		s    Stream[T]
+		args args
+		want [][]T
+	}
+	tests := []testCase[int]{
+		{
+			name: "sliding emit",
+			s:    FromVar(1, 2, 3, 4),
+			args: args{3, 1, PartialEmit},
+			want: [][]int{{1, 2, 3}, {2, 3, 4}, {3, 4}, {4}},
+		},
+		{
+			name: "sliding drop",
+			s:    FromVar(1, 2, 3, 4),
+			args: args{3, 1, PartialDrop},
+			want: [][]int{{1, 2, 3}, {2, 3, 4}},
+		},
+		{
+			name: "sliding pad",
+			s:    FromVar(1, 2, 3, 4),
+			args: args{3, 2, PartialPad},
+			want: [][]int{{1, 2, 3}, {3, 4, 0}},
+		},
+		{
+			name: "tumbling drop",
+			s:    FromVar(1, 2, 3, 4, 5),
+			args: args{2, 2, PartialDrop},
+			want: [][]int{{1, 2}, {3, 4}},
+		},
+		{
+			name: "hopping",
+			s:    FromVar(1, 2, 3, 4, 5, 6, 7, 8),
+			args: args{2, 3, PartialEmit},
+			want: [][]int{{1, 2}, {4, 5}, {7, 8}},
+		},
+		{
+			name: "short drop",
+			s:    FromVar(1, 2),
+			args: args{3, 1, PartialDrop},
+			want: [][]int{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Window(tt.s, tt.args.size, tt.args.step, tt.args.partial).Collect(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Window() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWindow_MovingAverage(t *testing.T) {
+	averages := Map(Window(FromVar(1.0, 2.0, 3.0, 4.0, 5.0), 3, 1, PartialDrop), func(window []float64) float64 {
+		sum := 0.0
+		for _, v := range window {
+			sum += v
+		}
+		return sum / float64(len(window))
+	}).Collect()
+
+	if want := []float64{2, 3, 4}; !reflect.DeepEqual(averages, want) {
+		t.Errorf("Window() = %v, want %v", averages, want)
+	}
+}
+
+func TestStream_ChunkAny(t *testing.T) {
+	got := CollectAs[[]int](FromVar(1, 2, 3).ChunkAny(2))
+	if want := [][]int{{1, 2}, {3}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ChunkAny() = %v, want %v", got, want)
+	}
+
+	got = CollectAs[[]int](FromVar(1, 2, 3).WindowAny(2, 1, PartialDrop))
+	if want := [][]int{{1, 2}, {2, 3}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("WindowAny() = %v, want %v", got, want)
+	}
+}
+
+// timedSource advances the clock by the delay before producing each element.
+type timedSource[T any] struct {
+	clock    *ManualClock
+	index    int
+	delays   []time.Duration
+	elements []T
+	hold     func(index int)
+}
+
+func newTimedSource[T any](clock *ManualClock, delays []time.Duration, elements ...T) *timedSource[T] {
+	return &timedSource[T]{
+		clock:    clock,
+		index:    -1,
+		delays:   delays,
+		elements: elements,
+	}
+}
+
+func (c *timedSource[T]) Next() bool {
+	if c.index+1 == len(c.elements) {
+		return false
+	}
+	c.index++
+	c.clock.Advance(c.delays[c.index])
+	if c.hold != nil {
+		c.hold(c.index)
+	}
+	return true
+}
+
+func (c *timedSource[T]) Get() T {
+	return c.elements[c.index]
+}
+
+func TestWindowByTime(t *testing.T) {
+	clock := NewManualClock(time.Unix(0, 0))
+	source := newTimedSource(clock, []time.Duration{0, time.Second, 2 * time.Second, 4 * time.Second},
+		"a", "b", "c", "d")
+
+	// [0s, 3s), [3s, 6s), [6s, 9s)
+	got := WindowByTime(FromSource[string](source), 3*time.Second, clock).Collect()
+	if want := [][]string{{"a", "b"}, {"c"}, {"d"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("WindowByTime() = %v, want %v", got, want)
+	}
+}
+
+func TestSlidingWindowByTime(t *testing.T) {
+	clock := NewManualClock(time.Unix(0, 0))
+	source := newTimedSource(clock, []time.Duration{500 * time.Millisecond, time.Second, time.Second},
+		"a", "b", "c")
+
+	// windows of 2s every 1s: [-1s, 1s), [0s, 2s), [1s, 3s)
+	got := SlidingWindowByTime(FromSource[string](source), 2*time.Second, time.Second, clock).Collect()
+	if want := [][]string{{"a"}, {"a", "b"}, {"b", "c"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SlidingWindowByTime() = %v, want %v", got, want)
+	}
+}
+
+func TestSessionWindow(t *testing.T) {
+	clock := NewManualClock(time.Unix(0, 0))
+	source := newTimedSource(clock, []time.Duration{0, time.Second, 3 * time.Second, time.Second},
+		"a", "b", "c", "d")
+
+	got := SessionWindow(FromSource[string](source), 2*time.Second, clock).Collect()
+	if want := [][]string{{"a", "b"}, {"c", "d"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SessionWindow() = %v, want %v", got, want)
+	}
+}
+
+func TestSessionWindow_Timeout(t *testing.T) {
+	clock := NewManualClock(time.Unix(0, 0))
+	release := make(chan struct{})
+	source := newTimedSource(clock, []time.Duration{0, time.Second, 3 * time.Second},
+		"a", "b", "c")
+	// the source stalls after the gap is over
+	source.hold = func(index int) {
+		if index == 2 {
+			<-release
+		}
+	}
+
+	windows := SessionWindow(FromSource[string](source), 2*time.Second, clock)
+	out := make(chan []string)
+	go func() {
+		for windows.Next() {
+			out <- windows.Get()
+		}
+		close(out)
+	}()
+
+	// the session is closed by the timeout while the source is stalled
+	if got, want := <-out, []string{"a", "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SessionWindow() = %v, want %v", got, want)
+	}
+	close(release)
+	if got, want := <-out, []string{"c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SessionWindow() = %v, want %v", got, want)
+	}
+	if got, ok := <-out; ok {
+		t.Errorf("SessionWindow() = %v, want end", got)
+	}
+}
+
+func TestWindowByTime_Chan(t *testing.T) {
+	jobs := make(chan int)
+	go func() {
+		for i := 0; i < 5; i++ {
+			jobs <- i
+		}
+		close(jobs)
+	}()
+
+	got := Fold(WindowByTime(FromChan(jobs), time.Hour, nil), []int{}, func(acc []int, window []int) []int {
+		return append(acc, window...)
+	})
+	if want := []int{0, 1, 2, 3, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("WindowByTime() = %v, want %v", got, want)
+	}
+}
+
+func TestTimeWindow_InvalidDuration(t *testing.T) {
+	clock := NewManualClock(time.Unix(0, 0))
+	tests := []struct {
+		name string
+		s    func(Stream[int]) Stream[[]int]
+	}{
+		{"WindowByTime zero", func(s Stream[int]) Stream[[]int] {
+			return WindowByTime(s, 0, clock)
+		}},
+		{"SlidingWindowByTime zero every", func(s Stream[int]) Stream[[]int] {
+			return SlidingWindowByTime(s, time.Second, 0, nil)
+		}},
+		{"SlidingWindowByTime negative size", func(s Stream[int]) Stream[[]int] {
+			return SlidingWindowByTime(s, -time.Second, time.Second, clock)
+		}},
+		{"SessionWindow zero", func(s Stream[int]) Stream[[]int] {
+			return SessionWindow(s, 0, clock)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer checkGoroutines(t)()
+
+			source := newClosingSource(1, 2, 3)
+			got, err := tt.s(FromSource[int](source)).CollectErr()
+			if len(got) != 0 || err == nil {
+				t.Errorf("%s = %v, %v, want an error", tt.name, got, err)
+			}
+			if source.Closed() != 1 {
+				t.Errorf("source closed %d times, want 1", source.Closed())
+			}
+		})
+	}
+}
+
+func TestSlidingWindowByTime_Idle(t *testing.T) {
+	clock := NewManualClock(time.Unix(0, 0))
+	// a long idle period between the elements does not tick every empty window
+	source := newTimedSource(clock, []time.Duration{0, 1000 * time.Hour}, "a", "b")
+
+	got := SlidingWindowByTime(FromSource[string](source), time.Nanosecond, time.Nanosecond, clock).Collect()
+	if want := [][]string{{"a"}, {"b"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SlidingWindowByTime() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,296 @@
+package stream
+
+import (
+	"sync"
+)
+
+// Zip returns a stream consisting of the pairs of the elements of the streams,
+// the stream ends when either of them ends.
+//
+//	with a=[1, 2, 3] and b=["a", "b"] produces [(1, a), (2, b)]
+func Zip[A, B any](a Stream[A], b Stream[B]) Stream[Pair[A, B]] {
+	return ZipWith[A, B, Pair[A, B]](a, b, PairOf[A, B])
+}
+
+// Zip3 returns a stream consisting of the triples of the elements of the streams,
+// the stream ends when any of them ends.
+func Zip3[A, B, C any](a Stream[A], b Stream[B], c Stream[C]) Stream[Triple[A, B, C]] {
+	return ZipWith[Pair[A, B], C, Triple[A, B, C]](Zip(a, b), c, func(p Pair[A, B], v C) Triple[A, B, C] {
+		return TripleOf(p.First, p.Second, v)
+	})
+}
+
+// ZipLongest returns a stream consisting of the pairs of the elements of the streams,
+// the stream ends when both of them end, and the missing elements of the shorter one are filled with fillA or fillB.
+// the stream ends at the first stream which fails.
+//
+//	with a=[1, 2, 3], b=["a", "b"] and fillB="-" produces [(1, a), (2, b), (3, -)]
+func ZipLongest[A, B any](a Stream[A], b Stream[B], fillA A, fillB B) Stream[Pair[A, B]] {
+	upA := toBase[A](a)
+	upB := toBase[B](b)
+	if upA == nil || upB == nil {
+		var nilstream *baseStream[Pair[A, B]]
+		return nilstream
+	}
+
+	var cur Pair[A, B]
+	var doneA, doneB, failed bool
+	zipstream := new(baseStream[Pair[A, B]])
+	zipstream.idx = -1
+	zipstream.next = func() bool {
+		cur = PairOf(fillA, fillB)
+		if !doneA {
+			if upA.next() {
+				cur.First = upA.get().(A)
+			} else {
+				doneA = true
+				failed = upA.geterr() != nil
+			}
+		}
+		if !doneB && !failed {
+			if upB.next() {
+				cur.Second = upB.get().(B)
+			} else {
+				doneB = true
+				failed = upB.geterr() != nil
+			}
+		}
+		if doneA && doneB || failed {
+			doneA, doneB = true, true
+			return false
+		}
+		zipstream.idx++
+		return true
+	}
+	zipstream.get = func() any {
+		return cur
+	}
+	zipstream.getonrecover = func() RecoverFunc {
+		return upA.getonrecover()
+	}
+	zipstream.geterr = func() error {
+		if err := upA.geterr(); err != nil {
+			return err
+		}
+		return upB.geterr()
+	}
+	zipstream.close = func() error {
+		err := upA.close()
+		if closeerr := upB.close(); err == nil {
+			err = closeerr
+		}
+		return err
+	}
+	return zipstream
+}
+
+// unzipper splits the pairs of the upstream into the buffers of the two sides.
+type unzipper[A, B any] struct {
+	mu      sync.Mutex
+	up      *baseStream[Pair[A, B]]
+	firsts  []A
+	seconds []B
+	closed  [2]bool
+	ended   bool
+}
+
+// pull pulls a pair of the upstream into the buffers of the sides which are not closed,
+// it returns false if the upstream ended.
+func (z *unzipper[A, B]) pull() bool {
+	if z.ended {
+		return false
+	}
+	if !z.up.next() {
+		z.ended = true
+		return false
+	}
+	p := z.up.get().(Pair[A, B])
+	if !z.closed[0] {
+		z.firsts = append(z.firsts, p.First)
+	}
+	if !z.closed[1] {
+		z.seconds = append(z.seconds, p.Second)
+	}
+	return true
+}
+
+// close closes a side, the upstream is closed when both sides are closed.
+func (z *unzipper[A, B]) close(side int) error {
+	z.mu.Lock()
+	defer z.mu.Unlock()
+
+	if z.closed[side] {
+		return nil
+	}
+	z.closed[side] = true
+	if side == 0 {
+		z.firsts = nil
+	} else {
+		z.seconds = nil
+	}
+	if z.closed[0] && z.closed[1] {
+		return z.up.close()
+	}
+	return nil
+}
+
+// newUnzipStream returns the stream of a side, which takes the elements from buf.
+func newUnzipStream[A, B, T any](z *unzipper[A, B], side int, buf *[]T) Stream[T] {
+	var cur T
+	unzipstream := new(baseStream[T])
+	unzipstream.idx = -1
+	unzipstream.next = func() bool {
+		z.mu.Lock()
+		defer z.mu.Unlock()
+
+		if z.closed[side] {
+			return false
+		}
+		for len(*buf) == 0 {
+			if !z.pull() {
+				return false
+			}
+		}
+		unzipstream.idx++
+		cur = (*buf)[0]
+		*buf = (*buf)[1:]
+		return true
+	}
+	unzipstream.get = func() any {
+		return cur
+	}
+	unzipstream.getonrecover = func() RecoverFunc {
+		return z.up.getonrecover()
+	}
+	unzipstream.geterr = func() error {
+		z.mu.Lock()
+		defer z.mu.Unlock()
+		return z.up.geterr()
+	}
+	unzipstream.close = func() error {
+		return z.close(side)
+	}
+	return unzipstream
+}
+
+// Unzip splits a stream of pairs into the streams of the first and the second values.
+// both streams replay all the values: the pairs pulled for one stream are buffered for the other,
+// so the streams can be consumed one after another or concurrently.
+// the upstream is closed when both streams are closed.
+//
+//	with [(1, a), (2, b)] produces [1, 2] and [a, b]
+func Unzip[A, B any](s Stream[Pair[A, B]]) (Stream[A], Stream[B]) {
+	up := toBase[Pair[A, B]](s)
+	if up == nil {
+		var nilA *baseStream[A]
+		var nilB *baseStream[B]
+		return nilA, nilB
+	}
+
+	z := &unzipper[A, B]{up: up}
+	return newUnzipStream(z, 0, &z.firsts), newUnzipStream(z, 1, &z.seconds)
+}
+
+// latestStream combines the latest elements of two streams pulled by their own goroutines.
+// emitOn is called with the index of the stream which produced an element, and returns true to emit the combined element.
+// endOn is called with the index of the stream which ended, and returns true to end the stream.
+func latestStream[A, B, R any](a Stream[A], b Stream[B], combine func(A, B) R, emitOn func(source int) bool, endOn func(source int) bool) Stream[R] {
+	upA := toBase[A](a)
+	upB := toBase[B](b)
+	if upA == nil || upB == nil {
+		var nilstream *baseStream[R]
+		return nilstream
+	}
+
+	u := newUpstreams([]Stream[Pair[A, B]]{
+		Map(Stream[A](upA), func(v A) Pair[A, B] {
+			return Pair[A, B]{First: v}
+		}),
+		Map(Stream[B](upB), func(v B) Pair[A, B] {
+			return Pair[A, B]{Second: v}
+		}),
+	})
+	m := newMerger(u)
+	var latest Pair[A, B]
+	var present [2]bool
+	var cur R
+	done := false
+
+	combinestream := new(baseStream[R])
+	combinestream.idx = -1
+	combinestream.next = func() bool {
+		for !done {
+			event, ok := m.next()
+			if !ok {
+				done = true
+				break
+			}
+			if event.ended {
+				if endOn(event.source) {
+					done = true
+					m.stop()
+				}
+				continue
+			}
+			if event.source == 0 {
+				latest.First = event.value.First
+			} else {
+				latest.Second = event.value.Second
+			}
+			present[event.source] = true
+			if present[0] && present[1] && emitOn(event.source) {
+				combinestream.idx++
+				cur = combine(latest.First, latest.Second)
+				return true
+			}
+		}
+		return false
+	}
+	combinestream.get = func() any {
+		return cur
+	}
+	combinestream.getonrecover = func() RecoverFunc {
+		return u.onrecover()
+	}
+	combinestream.geterr = func() error {
+		return m.err()
+	}
+	combinestream.close = func() error {
+		done = true
+		return m.stop()
+	}
+	return combinestream
+}
+
+// CombineLatest returns a stream consisting of the results of applying the given function to
+// the latest elements of the streams whenever either of them produces an element, after both have produced one.
+// each stream is pulled by its own goroutine like Merge, so it is meant for channel-backed streams.
+// the stream ends when both streams end.
+//
+//	with a=[1, _, 2] and b=[_, x, _, y] in time produces [(1, x), (2, x), (2, y)]
+func CombineLatest[A, B, R any](a Stream[A], b Stream[B], combine func(A, B) R) Stream[R] {
+	return latestStream(a, b, combine,
+		func(int) bool {
+			return true
+		},
+		func(int) bool {
+			return false
+		})
+}
+
+// WithLatestFrom returns a stream consisting of the results of applying the given function to
+// each element of the stream and the latest element of other,
+// the elements of the stream before other produces one are dropped.
+// both streams are pulled by their own goroutines like Merge, so it is meant for channel-backed streams.
+// the stream ends when the stream ends, then other is closed.
+//
+//	with s=[_, 1, _, 2] and other=[x, _, y] in time produces [(1, x), (2, y)]
+func WithLatestFrom[T, U, R any](s Stream[T], other Stream[U], combine func(T, U) R) Stream[R] {
+	return latestStream(s, other, combine,
+		func(source int) bool {
+			return source == 0
+		},
+		func(source int) bool {
+			return source == 0
+		})
+}
@@ -0,0 +1,216 @@
+package stream
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestTuple(t *testing.T) {
+	p := PairOf(1, "a")
+	if first, second := p.Values(); first != 1 || second != "a" {
+		t.Errorf("Values() = %v, %v, want 1, a", first, second)
+	}
+	if got := p.String(); got != "(1, a)" {
+		t.Errorf("String() = %v, want (1, a)", got)
+	}
+	tr := TripleOf(1, "a", true)
+	if first, second, third := tr.Values(); first != 1 || second != "a" || !third {
+		t.Errorf("Values() = %v, %v, %v, want 1, a, true", first, second, third)
+	}
+	if got := tr.String(); got != "(1, a, true)" {
+		t.Errorf("String() = %v, want (1, a, true)", got)
+	}
+}
+
+func TestZip(t *testing.T) {
+	type testCase[T any] struct {
+		name string
+		s    Stream[T]
+		want []T
+	}
+	tests := []testCase[Pair[int, string]]{
+		{"shorter", Zip(FromVar(1, 2, 3), FromVar("a", "b")), []Pair[int, string]{{1, "a"}, {2, "b"}}},
+		{"empty", Zip(FromVar[int](), FromVar("a")), []Pair[int, string]{}},
+		{"longest a", ZipLongest(FromVar(1, 2, 3), FromVar("a"), -1, "-"), []Pair[int, string]{{1, "a"}, {2, "-"}, {3, "-"}}},
+		{"longest b", ZipLongest(FromVar(1), FromVar("a", "b"), -1, "-"), []Pair[int, string]{{1, "a"}, {-1, "b"}}},
+		{"longest empty", ZipLongest(FromVar[int](), FromVar[string](), -1, "-"), []Pair[int, string]{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.s.Collect(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Zip() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestZip3(t *testing.T) {
+	got := Zip3(FromVar(1, 2), FromVar("a", "b", "c"), FromVar(true, false)).Collect()
+	want := []Triple[int, string, bool]{{1, "a", true}, {2, "b", false}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Zip3() = %v, want %v", got, want)
+	}
+}
+
+func TestZip_Err(t *testing.T) {
+	failure := errors.New("failure")
+	got, err := ZipLongest(FromSource[int](newFailingSource(failure, 1)), FromVar("a", "b", "c"), 0, "").CollectErr()
+	if want := []Pair[int, string]{{1, "a"}}; !errors.Is(err, failure) || !reflect.DeepEqual(got, want) {
+		t.Errorf("ZipLongest() = %v, %v, want %v, %v", got, err, want, failure)
+	}
+
+	_, err = Zip(FromVar(1, 2), FromSource[string](newFailingSource(failure, "a"))).CollectErr()
+	if !errors.Is(err, failure) {
+		t.Errorf("Zip() error = %v, want %v", err, failure)
+	}
+}
+
+func TestZip_Close(t *testing.T) {
+	a, b := newClosingSource(1, 2), newClosingSource("a")
+	ZipLongest(FromSource[int](a), FromSource[string](b), 0, "").Take(1).Collect()
+	if a.Closed() != 1 || b.Closed() != 1 {
+		t.Errorf("closed %d, %d times, want 1, 1", a.Closed(), b.Closed())
+	}
+}
+
+func TestUnzip(t *testing.T) {
+	pairs := func() Stream[Pair[int, string]] {
+		return FromVar(PairOf(1, "a"), PairOf(2, "b"), PairOf(3, "c"))
+	}
+
+	firsts, seconds := Unzip(pairs())
+	if got, want := firsts.Collect(), []int{1, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Unzip() firsts = %v, want %v", got, want)
+	}
+	if got, want := seconds.Collect(), []string{"a", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Unzip() seconds = %v, want %v", got, want)
+	}
+
+	firsts, seconds = Unzip(pairs())
+	if got, want := seconds.Take(1).Collect(), []string{"a"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Unzip() seconds = %v, want %v", got, want)
+	}
+	if got, want := firsts.Collect(), []int{1, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Unzip() firsts = %v, want %v", got, want)
+	}
+}
+
+func TestUnzip_Concurrent(t *testing.T) {
+	firsts, seconds := Unzip(Map(Range(0, 1000, 1), func(v int) Pair[int, int] {
+		return PairOf(v, -v)
+	}))
+
+	var wg sync.WaitGroup
+	var sumFirsts, sumSeconds int
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		sumFirsts = firsts.Fold(0, func(acc, ele int) int { return acc + ele })
+	}()
+	go func() {
+		defer wg.Done()
+		sumSeconds = seconds.Fold(0, func(acc, ele int) int { return acc + ele })
+	}()
+	wg.Wait()
+	if sumFirsts != 499500 || sumSeconds != -499500 {
+		t.Errorf("Unzip() sums = %v, %v, want 499500, -499500", sumFirsts, sumSeconds)
+	}
+}
+
+func TestUnzip_Close(t *testing.T) {
+	source := newClosingSource(PairOf(1, "a"), PairOf(2, "b"))
+	firsts, seconds := Unzip(FromSource[Pair[int, string]](source))
+
+	firsts.Take(1).Collect()
+	if source.Closed() != 0 {
+		t.Errorf("closed %d times before both streams are closed, want 0", source.Closed())
+	}
+	if got, want := seconds.Collect(), []string{"a", "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Unzip() seconds = %v, want %v", got, want)
+	}
+	if source.Closed() != 1 {
+		t.Errorf("closed %d times, want 1", source.Closed())
+	}
+}
+
+func TestCombineLatest(t *testing.T) {
+	defer checkGoroutines(t)()
+
+	chA := make(chan int)
+	chB := make(chan string)
+	combined := CombineLatest(FromChan(chA), FromChan(chB), PairOf[int, string]).ToChan(0)
+
+	chA <- 1
+	chB <- "x"
+	want := []Pair[int, string]{{1, "x"}, {2, "x"}, {2, "y"}, {3, "y"}}
+	if got := <-combined; got != want[0] {
+		t.Errorf("CombineLatest() = %v, want %v", got, want[0])
+	}
+	chA <- 2
+	if got := <-combined; got != want[1] {
+		t.Errorf("CombineLatest() = %v, want %v", got, want[1])
+	}
+	chB <- "y"
+	if got := <-combined; got != want[2] {
+		t.Errorf("CombineLatest() = %v, want %v", got, want[2])
+	}
+	close(chB)
+	chA <- 3
+	if got := <-combined; got != want[3] {
+		t.Errorf("CombineLatest() = %v, want %v", got, want[3])
+	}
+	close(chA)
+	if got, ok := <-combined; ok {
+		t.Errorf("CombineLatest() = %v, want the end", got)
+	}
+}
+
+func TestWithLatestFrom(t *testing.T) {
+	defer checkGoroutines(t)()
+
+	ch := make(chan int)
+	other := newClosingSource("x")
+	latest := WithLatestFrom(FromChan(ch), FromSource[string](other), PairOf[int, string]).ToChan(0)
+
+	// other ends after x is delivered
+	other.waitClosed(t)
+	ch <- 1
+	ch <- 2
+	close(ch)
+
+	var got []Pair[int, string]
+	for v := range latest {
+		got = append(got, v)
+	}
+	if want := []Pair[int, string]{{1, "x"}, {2, "x"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("WithLatestFrom() = %v, want %v", got, want)
+	}
+}
+
+func TestWithLatestFrom_End(t *testing.T) {
+	defer checkGoroutines(t)()
+
+	other := newClosingSource[string]()
+	got := WithLatestFrom(FromVar(1, 2), Concat[string](FromSource[string](other), Repeat("x", -1)), PairOf[int, string]).Collect()
+	if len(got) > 2 {
+		t.Errorf("WithLatestFrom() = %v, want at most 2 elements", got)
+	}
+	other.waitClosed(t)
+}
+
+func TestWithLatestFrom_ErrEarlyExit(t *testing.T) {
+	defer checkGoroutines(t)()
+
+	// Err is called after the stream ends while other is still pulled, run with -race
+	got, err := WithLatestFrom(FromVar(1, 2), Repeat("x", -1), PairOf[int, string]).CollectErr()
+	if len(got) > 2 || err != nil {
+		t.Errorf("WithLatestFrom() = %v, %v, want at most 2 elements", got, err)
+	}
+
+	got, err = CombineLatest(FromVar(1), Repeat("x", -1), PairOf[int, string]).Take(3).CollectErr()
+	if len(got) != 3 || err != nil {
+		t.Errorf("CombineLatest() = %v, %v, want 3 elements", got, err)
+	}
+}